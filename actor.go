@@ -0,0 +1,50 @@
+package servers
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Actor returns the pair of functions serving and stopping the launcher, which fits Add of run.Group of oklog/run.
+// Execute serves the launcher and, once it is interrupted, waits for Stop to finish, so that run.Group returns
+// after the launcher is drained, the error of Stop (except ErrAlreadyStopped) is returned by execute.
+// Interrupt stops the launcher within timeout (unlimited, if it is zero), it can be called several times.
+func Actor(launcher Launcher, timeout time.Duration) (execute func() error, interrupt func(error)) {
+	interrupted := make(chan struct{})
+	stopped := make(chan error, 1)
+	once := new(sync.Once)
+
+	execute = func() error {
+		err := launcher.Serve()
+
+		select {
+		case <-interrupted:
+			err = errors.Join(err, <-stopped)
+		default:
+		}
+		return err
+	}
+
+	interrupt = func(error) {
+		once.Do(func() {
+			close(interrupted)
+
+			ctx := context.Background()
+			if timeout != 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, timeout)
+				defer cancel()
+			}
+
+			err := launcher.Stop(ctx)
+			if errors.Is(err, ErrAlreadyStopped) {
+				err = nil
+			}
+			stopped <- err
+		})
+	}
+
+	return execute, interrupt
+}
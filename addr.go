@@ -0,0 +1,21 @@
+package servers
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+)
+
+// ValidateAddr validates the listen address, which is "host:port" with the optional host:
+// ":8080", "0.0.0.0:8080", "[::1]:8080" and "localhost:0" are valid, port 0 binds an ephemeral port.
+func ValidateAddr(addr string) error {
+	_, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("invalid address %q: %w", addr, err)
+	}
+
+	if _, err = strconv.ParseUint(port, 10, 16); err != nil {
+		return fmt.Errorf("invalid port of address %q", addr)
+	}
+	return nil
+}
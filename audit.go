@@ -0,0 +1,241 @@
+package servers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// AuditAction is the lifecycle transition of the launcher recorded by WithAudit.
+type AuditAction string
+
+// Actions of the audit events.
+const (
+	// AuditServe is recorded once Serve is called, together with the snapshot of the config.
+	AuditServe AuditAction = "serve"
+	// AuditReady is recorded once the launcher is ready to serve, if it is Readier.
+	AuditReady AuditAction = "ready"
+	// AuditExit is recorded once Serve returns.
+	AuditExit AuditAction = "exit"
+	// AuditStop is recorded once Stop is called, together with the caller of Stop.
+	AuditStop AuditAction = "stop"
+	// AuditStopped is recorded once Stop returns.
+	AuditStopped AuditAction = "stopped"
+)
+
+// AuditEvent represents the lifecycle transition of the launcher.
+// Launcher and Labels describe the launcher, if it is Named.
+// Actor is the one set by WithAuditActor into ctx of Stop, Caller is the function calling Stop outside this package.
+// Config is the snapshot of the config of AuditServe.
+// Duration is the time elapsed since Serve was called (AuditReady and AuditExit) or Stop was called (AuditStopped).
+// Err is the error returned by Serve or Stop.
+type AuditEvent struct {
+	Time     time.Time
+	Launcher string
+	Labels   map[string]string
+	Action   AuditAction
+	Actor    string
+	Caller   string
+	Config   interface{}
+	Duration time.Duration
+	Err      error
+}
+
+// AuditSink delivers an interface to the storage of the audit events, e.g. an append-only file or a database.
+type AuditSink interface {
+	// Record stores the event, it is called synchronously by the transitions of the launcher.
+	Record(event AuditEvent) error
+}
+
+// AuditSinkFunc adapts the function to AuditSink.
+type AuditSinkFunc func(event AuditEvent) error
+
+// Record stores the event.
+func (f AuditSinkFunc) Record(event AuditEvent) error {
+	return f(event)
+}
+
+// loggerAuditSink predetermines the consistency of the implementation AuditSink on top of Logger.
+type loggerAuditSink struct {
+	logger Logger
+}
+
+// Record logs the event.
+func (s loggerAuditSink) Record(event AuditEvent) error {
+	keysAndValues := []interface{}{"time", event.Time, "action", event.Action}
+	if event.Launcher != "" {
+		keysAndValues = append(keysAndValues, "launcher", event.Launcher)
+	}
+	for key, value := range event.Labels {
+		keysAndValues = append(keysAndValues, key, value)
+	}
+	if event.Actor != "" {
+		keysAndValues = append(keysAndValues, "actor", event.Actor)
+	}
+	if event.Caller != "" {
+		keysAndValues = append(keysAndValues, "caller", event.Caller)
+	}
+	if event.Config != nil {
+		keysAndValues = append(keysAndValues, "config", fmt.Sprintf("%+v", event.Config))
+	}
+	if event.Duration != 0 {
+		keysAndValues = append(keysAndValues, "duration", event.Duration)
+	}
+	if event.Err != nil {
+		keysAndValues = append(keysAndValues, "error", event.Err)
+	}
+
+	s.logger.Info("audit", keysAndValues...)
+	return nil
+}
+
+// NewLoggerAuditSink - constructor AuditSink, which logs the events to the logger, e.g. NewJSONLogger(file).
+func NewLoggerAuditSink(logger Logger) AuditSink {
+	return loggerAuditSink{logger: logger}
+}
+
+// AuditConfig delivers a set of settings for WithAudit.
+// Config is the snapshot of the config of the launcher recorded by AuditServe, it is better passed by value.
+// OnError, if set, receives the failures of Sink, except the one of AuditServe, which refuses Serve.
+type AuditConfig struct {
+	Sink    AuditSink
+	Config  interface{}
+	OnError func(err error)
+}
+
+// Validate validates AuditConfig according to predefined rules.
+func (c AuditConfig) Validate() error {
+	var errs []error
+
+	if c.Sink == nil {
+		errs = append(errs, errors.New("Sink can't be nil"))
+	}
+	return errors.Join(errs...)
+}
+
+// actorKey is the key of the actor of Stop in the context.
+type actorKey struct{}
+
+// WithAuditActor returns the context, which names the actor (e.g. the operator or the deployment) of Stop
+// in the audit events.
+func WithAuditActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// audited predetermines the consistency of the implementation Launcher, which records its lifecycle transitions.
+type audited struct {
+	launcher Launcher
+	config   AuditConfig
+}
+
+// Serve records the transitions of serving the launcher.
+// Serve is refused, if AuditServe isn't recorded.
+func (a audited) Serve() error {
+	started := time.Now()
+
+	event := a.event(AuditServe)
+	event.Config = a.config.Config
+	if err := a.config.Sink.Record(event); err != nil {
+		return fmt.Errorf("can't record audit event: %w", err)
+	}
+
+	exited := make(chan struct{})
+	defer close(exited)
+	go func() {
+		select {
+		case <-ready(a.launcher):
+			event := a.event(AuditReady)
+			event.Duration = time.Since(started)
+			a.record(event)
+		case <-exited:
+		}
+	}()
+
+	err := a.launcher.Serve()
+
+	event = a.event(AuditExit)
+	event.Duration = time.Since(started)
+	event.Err = err
+	a.record(event)
+
+	return err
+}
+
+// Stop records the transitions of stopping the launcher.
+func (a audited) Stop(ctx context.Context) error {
+	started := time.Now()
+
+	event := a.event(AuditStop)
+	event.Actor, _ = ctx.Value(actorKey{}).(string)
+	event.Caller = caller()
+	a.record(event)
+
+	err := a.launcher.Stop(ctx)
+
+	event = a.event(AuditStopped)
+	event.Duration = time.Since(started)
+	event.Err = err
+	a.record(event)
+
+	return err
+}
+
+// Unwrap returns the wrapped launcher.
+func (a audited) Unwrap() Launcher {
+	return a.launcher
+}
+
+// event returns the event of the action describing the launcher.
+func (a audited) event(action AuditAction) AuditEvent {
+	event := AuditEvent{
+		Time:   time.Now(),
+		Action: action,
+	}
+	if named, ok := as[Named](a.launcher); ok {
+		event.Launcher = named.Name()
+		event.Labels = named.Labels()
+	}
+	return event
+}
+
+// record records the event, the failure is passed to OnError.
+func (a audited) record(event AuditEvent) {
+	err := a.config.Sink.Record(event)
+	if err != nil && a.config.OnError != nil {
+		a.config.OnError(fmt.Errorf("can't record audit event %s: %w", event.Action, err))
+	}
+}
+
+// caller returns the first function on the stack outside this package, e.g. the one calling Run or Group.Stop.
+func caller() string {
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	last := ""
+	for {
+		frame, more := frames.Next()
+		if frame.Function != "" {
+			last = fmt.Sprintf("%s (%s:%d)", frame.Function, frame.File, frame.Line)
+			if !strings.HasPrefix(frame.Function, "github.com/golang-mixins/servers.") {
+				return last
+			}
+		}
+		if !more {
+			return last
+		}
+	}
+}
+
+// WithAudit wraps the launcher to record its lifecycle transitions into the sink.
+func WithAudit(launcher Launcher, cfg AuditConfig) (Launcher, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return audited{
+		launcher: launcher,
+		config:   cfg,
+	}, nil
+}
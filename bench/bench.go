@@ -0,0 +1,253 @@
+// Package bench provides the load harness of servers implementations: it serves the launcher, drives
+// the concurrent HTTP load against it and stops it under the load, reporting the latency percentiles
+// and the drain time, so that the performance regressions of the server layer are measurable in the tests.
+package bench
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golang-mixins/servers"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Config delivers a set of settings for Run.
+// Launcher is served by Run and stopped under the load once Duration elapses, its readiness is awaited,
+// if it is servers.Readier. URL is requested by Concurrency workers with Method (GET by default) using Client
+// (http.DefaultClient by default), so the launcher binding the ephemeral port has to be listening already
+// (e.g. by Listen of http/std) to build it. StopTimeout bounds Stop (unlimited by default).
+type Config struct {
+	Launcher    servers.Launcher
+	URL         string
+	Method      string
+	Client      *http.Client
+	Concurrency int
+	Duration    time.Duration
+	StopTimeout time.Duration
+}
+
+// Validate validates Config according to predefined rules.
+func (c Config) Validate() error {
+	var errs []error
+
+	if c.Launcher == nil {
+		errs = append(errs, errors.New("Launcher can't be nil"))
+	}
+
+	if c.URL == "" {
+		errs = append(errs, errors.New("URL can't be empty"))
+	}
+
+	if c.Concurrency <= 0 {
+		errs = append(errs, errors.New("Concurrency must be positive"))
+	}
+
+	if c.Duration <= 0 {
+		errs = append(errs, errors.New("Duration must be positive"))
+	}
+
+	if c.StopTimeout < 0 {
+		errs = append(errs, errors.New("StopTimeout can't be negative"))
+	}
+	return errors.Join(errs...)
+}
+
+// Report represents the results of Run.
+// Requests and Errors count the requests sent before Stop, DrainRequests and DrainErrors count the ones sent
+// while the launcher was stopping. Latencies are the sorted latencies of the successful requests.
+// DrainTime is the duration of Stop under the load, StopErr is the error of Stop.
+type Report struct {
+	Requests      int
+	Errors        int
+	DrainRequests int
+	DrainErrors   int
+	Latencies     []time.Duration
+	Throughput    float64
+	DrainTime     time.Duration
+	StopErr       error
+}
+
+// Percentile returns the latency of the percentile (e.g. 0.99), zero if there are no latencies.
+func (r Report) Percentile(percentile float64) time.Duration {
+	if len(r.Latencies) == 0 {
+		return 0
+	}
+
+	i := int(math.Ceil(percentile*float64(len(r.Latencies)))) - 1
+	if i < 0 {
+		i = 0
+	}
+	if i >= len(r.Latencies) {
+		i = len(r.Latencies) - 1
+	}
+	return r.Latencies[i]
+}
+
+// String returns the summary of the report.
+func (r Report) String() string {
+	return fmt.Sprintf("requests=%d errors=%d throughput=%.1f/s p50=%s p90=%s p99=%s max=%s "+
+		"drain=%s drain_requests=%d drain_errors=%d stop_error=%v",
+		r.Requests, r.Errors, r.Throughput, r.Percentile(0.5), r.Percentile(0.9), r.Percentile(0.99),
+		r.Percentile(1), r.DrainTime, r.DrainRequests, r.DrainErrors, r.StopErr)
+}
+
+// result is the result of the request.
+type result struct {
+	latency  time.Duration
+	err      error
+	draining bool
+}
+
+// Run serves the launcher, drives the load against it for Duration, stops it under the load and reports the results.
+// The error is returned, if the launcher fails to serve, or ctx is done before the launcher is ready.
+func Run(ctx context.Context, cfg Config) (Report, error) {
+	if err := cfg.Validate(); err != nil {
+		return Report{}, err
+	}
+
+	method := cfg.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	client := cfg.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	serving := make(chan error, 1)
+	go func() {
+		serving <- cfg.Launcher.Serve()
+	}()
+
+	if readier, ok := cfg.Launcher.(servers.Readier); ok {
+		select {
+		case <-readier.Ready():
+		case err := <-serving:
+			return Report{}, fmt.Errorf("can't serve: %w", err)
+		case <-ctx.Done():
+			cfg.Launcher.Stop(context.Background())
+			<-serving
+			return Report{}, ctx.Err()
+		}
+	}
+
+	load, stopLoad := context.WithCancel(ctx)
+	defer stopLoad()
+
+	mutex := new(sync.Mutex)
+	draining := false
+	results := make(chan result, cfg.Concurrency)
+	wg := new(sync.WaitGroup)
+	wg.Add(cfg.Concurrency)
+	for i := 0; i < cfg.Concurrency; i++ {
+		go func() {
+			defer wg.Done()
+			for load.Err() == nil {
+				mutex.Lock()
+				sent := result{draining: draining}
+				mutex.Unlock()
+
+				sent.latency, sent.err = request(load, client, method, cfg.URL)
+				if load.Err() != nil {
+					return
+				}
+				results <- sent
+			}
+		}()
+	}
+
+	var report Report
+	collected := make(chan struct{})
+	go func() {
+		defer close(collected)
+		for result := range results {
+			switch {
+			case result.draining && result.err != nil:
+				report.DrainRequests++
+				report.DrainErrors++
+			case result.draining:
+				report.DrainRequests++
+				report.Latencies = append(report.Latencies, result.latency)
+			case result.err != nil:
+				report.Requests++
+				report.Errors++
+			default:
+				report.Requests++
+				report.Latencies = append(report.Latencies, result.latency)
+			}
+		}
+	}()
+
+	started := time.Now()
+	timer := time.NewTimer(cfg.Duration)
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+		timer.Stop()
+	}
+	elapsed := time.Since(started)
+
+	mutex.Lock()
+	draining = true
+	mutex.Unlock()
+
+	stopCtx := context.WithoutCancel(ctx)
+	if cfg.StopTimeout != 0 {
+		var cancel context.CancelFunc
+		stopCtx, cancel = context.WithTimeout(stopCtx, cfg.StopTimeout)
+		defer cancel()
+	}
+
+	stopped := time.Now()
+	stopErr := cfg.Launcher.Stop(stopCtx)
+	drainTime := time.Since(stopped)
+
+	stopLoad()
+	wg.Wait()
+	close(results)
+	<-collected
+	serveErr := <-serving
+
+	sort.Slice(report.Latencies, func(i, j int) bool {
+		return report.Latencies[i] < report.Latencies[j]
+	})
+	report.Throughput = float64(report.Requests) / elapsed.Seconds()
+	report.DrainTime = drainTime
+	report.StopErr = stopErr
+
+	if serveErr != nil {
+		return report, fmt.Errorf("error serving: %w", serveErr)
+	}
+	return report, nil
+}
+
+// request sends the request and reads the response, the statuses other than 2xx and 3xx are reported as errors.
+func request(ctx context.Context, client *http.Client, method, url string) (time.Duration, error) {
+	started := time.Now()
+
+	request, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+
+	if _, err = io.Copy(io.Discard, response.Body); err != nil {
+		return 0, err
+	}
+	latency := time.Since(started)
+
+	if response.StatusCode >= http.StatusBadRequest {
+		return latency, fmt.Errorf("unexpected status %d", response.StatusCode)
+	}
+	return latency, nil
+}
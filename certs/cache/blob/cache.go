@@ -0,0 +1,74 @@
+// Package cache provides an implementation of autocert.Cache storing the certificates in a bucket
+// of the object storage (S3, GCS, Azure Blob, etc.) opened by gocloud.dev/blob, so that the replicas share them.
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gocloud.dev/blob"
+	"gocloud.dev/gcerrors"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// Config delivers a set of settings for Cache implementation.
+// Prefix, if set, prefixes the keys of the objects, e.g. "autocert/".
+type Config struct {
+	Bucket *blob.Bucket
+	Prefix string
+}
+
+// Validate validates Config according to predefined rules.
+func (c Config) Validate() error {
+	var errs []error
+
+	if c.Bucket == nil {
+		errs = append(errs, errors.New("Bucket can't be nil"))
+	}
+	return errors.Join(errs...)
+}
+
+// Cache predetermines the consistency of the implementation autocert.Cache.
+// Using the methods of the structure, without being initialized by the New() constructor, will lead to panic.
+type Cache struct {
+	bucket *blob.Bucket
+	prefix string
+}
+
+// Get returns the data stored by key, or autocert.ErrCacheMiss, if there is none.
+func (c *Cache) Get(ctx context.Context, key string) ([]byte, error) {
+	data, err := c.bucket.ReadAll(ctx, c.prefix+key)
+	if gcerrors.Code(err) == gcerrors.NotFound {
+		return nil, autocert.ErrCacheMiss
+	}
+	if err != nil {
+		return nil, fmt.Errorf("can't read %s: %w", key, err)
+	}
+	return data, nil
+}
+
+// Put stores the data by key.
+func (c *Cache) Put(ctx context.Context, key string, data []byte) error {
+	if err := c.bucket.WriteAll(ctx, c.prefix+key, data, nil); err != nil {
+		return fmt.Errorf("can't write %s: %w", key, err)
+	}
+	return nil
+}
+
+// Delete removes the data stored by key, the missing data isn't an error.
+func (c *Cache) Delete(ctx context.Context, key string) error {
+	err := c.bucket.Delete(ctx, c.prefix+key)
+	if err != nil && gcerrors.Code(err) != gcerrors.NotFound {
+		return fmt.Errorf("can't delete %s: %w", key, err)
+	}
+	return nil
+}
+
+// New - constructor Cache.
+func New(cfg Config) (*Cache, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &Cache{bucket: cfg.Bucket, prefix: cfg.Prefix}, nil
+}
@@ -0,0 +1,79 @@
+// Package cache provides an implementation of autocert.Cache storing the certificates in Consul KV,
+// so that the replicas share them.
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/consul/api"
+	"golang.org/x/crypto/acme/autocert"
+	"strings"
+)
+
+// Config delivers a set of settings for Cache implementation.
+// Prefix is the KV path the keys are stored under ("autocert" by default).
+type Config struct {
+	Client *api.Client
+	Prefix string
+}
+
+// Validate validates Config according to predefined rules.
+func (c Config) Validate() error {
+	var errs []error
+
+	if c.Client == nil {
+		errs = append(errs, errors.New("Client can't be nil"))
+	}
+	return errors.Join(errs...)
+}
+
+// Cache predetermines the consistency of the implementation autocert.Cache.
+// Using the methods of the structure, without being initialized by the New() constructor, will lead to panic.
+type Cache struct {
+	kv     *api.KV
+	prefix string
+}
+
+// Get returns the data stored by key, or autocert.ErrCacheMiss, if there is none.
+func (c *Cache) Get(ctx context.Context, key string) ([]byte, error) {
+	pair, _, err := c.kv.Get(c.prefix+key, new(api.QueryOptions).WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("can't get %s: %w", key, err)
+	}
+	if pair == nil {
+		return nil, autocert.ErrCacheMiss
+	}
+	return pair.Value, nil
+}
+
+// Put stores the data by key.
+func (c *Cache) Put(ctx context.Context, key string, data []byte) error {
+	pair := &api.KVPair{Key: c.prefix + key, Value: data}
+	if _, err := c.kv.Put(pair, new(api.WriteOptions).WithContext(ctx)); err != nil {
+		return fmt.Errorf("can't put %s: %w", key, err)
+	}
+	return nil
+}
+
+// Delete removes the data stored by key.
+func (c *Cache) Delete(ctx context.Context, key string) error {
+	if _, err := c.kv.Delete(c.prefix+key, new(api.WriteOptions).WithContext(ctx)); err != nil {
+		return fmt.Errorf("can't delete %s: %w", key, err)
+	}
+	return nil
+}
+
+// New - constructor Cache.
+func New(cfg Config) (*Cache, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	prefix := strings.Trim(cfg.Prefix, "/")
+	if prefix == "" {
+		prefix = "autocert"
+	}
+
+	return &Cache{kv: cfg.Client.KV(), prefix: prefix + "/"}, nil
+}
@@ -0,0 +1,129 @@
+// Package cache provides an implementation of autocert.Cache storing the certificates in a table
+// of the SQL database, so that the replicas share them.
+package cache
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/acme/autocert"
+	"regexp"
+)
+
+// Placeholders is the style of the query parameters of the database driver.
+type Placeholders int
+
+const (
+	// Question is the style of MySQL and SQLite: ?.
+	Question Placeholders = iota
+	// Dollar is the style of PostgreSQL: $1.
+	Dollar
+)
+
+// tableName validates the name of the table, which can't be passed as a query parameter.
+var tableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// Config delivers a set of settings for Cache implementation.
+// Table ("autocert_cache" by default) must exist and have the columns name (text primary key) and data (binary),
+// e.g. for PostgreSQL: CREATE TABLE autocert_cache (name TEXT PRIMARY KEY, data BYTEA NOT NULL).
+type Config struct {
+	DB           *sql.DB
+	Table        string
+	Placeholders Placeholders
+}
+
+// Validate validates Config according to predefined rules.
+func (c Config) Validate() error {
+	var errs []error
+
+	if c.DB == nil {
+		errs = append(errs, errors.New("DB can't be nil"))
+	}
+
+	if c.Table != "" && !tableName.MatchString(c.Table) {
+		errs = append(errs, fmt.Errorf("invalid Table %q", c.Table))
+	}
+
+	if c.Placeholders != Question && c.Placeholders != Dollar {
+		errs = append(errs, errors.New("unknown Placeholders"))
+	}
+	return errors.Join(errs...)
+}
+
+// Cache predetermines the consistency of the implementation autocert.Cache.
+// Put replaces the row by delete and insert within a transaction, so that no upsert dialect is required.
+// Using the methods of the structure, without being initialized by the New() constructor, will lead to panic.
+type Cache struct {
+	db     *sql.DB
+	get    string
+	insert string
+	delete string
+}
+
+// Get returns the data stored by key, or autocert.ErrCacheMiss, if there is none.
+func (c *Cache) Get(ctx context.Context, key string) ([]byte, error) {
+	var data []byte
+	err := c.db.QueryRowContext(ctx, c.get, key).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, autocert.ErrCacheMiss
+	}
+	if err != nil {
+		return nil, fmt.Errorf("can't get %s: %w", key, err)
+	}
+	return data, nil
+}
+
+// Put stores the data by key.
+func (c *Cache) Put(ctx context.Context, key string, data []byte) error {
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("can't begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.ExecContext(ctx, c.delete, key); err != nil {
+		return fmt.Errorf("can't delete %s: %w", key, err)
+	}
+
+	if _, err = tx.ExecContext(ctx, c.insert, key, data); err != nil {
+		return fmt.Errorf("can't insert %s: %w", key, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("can't commit transaction: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the data stored by key.
+func (c *Cache) Delete(ctx context.Context, key string) error {
+	if _, err := c.db.ExecContext(ctx, c.delete, key); err != nil {
+		return fmt.Errorf("can't delete %s: %w", key, err)
+	}
+	return nil
+}
+
+// New - constructor Cache.
+func New(cfg Config) (*Cache, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	table := cfg.Table
+	if table == "" {
+		table = "autocert_cache"
+	}
+
+	first, second := "?", "?"
+	if cfg.Placeholders == Dollar {
+		first, second = "$1", "$2"
+	}
+
+	return &Cache{
+		db:     cfg.DB,
+		get:    "SELECT data FROM " + table + " WHERE name = " + first,
+		insert: "INSERT INTO " + table + " (name, data) VALUES (" + first + ", " + second + ")",
+		delete: "DELETE FROM " + table + " WHERE name = " + first,
+	}, nil
+}
@@ -0,0 +1,84 @@
+package certs
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"github.com/golang-mixins/servers"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+// CAFileConfig delivers a set of settings for CAFile implementation.
+type CAFileConfig struct {
+	File     string
+	Interval time.Duration
+	Logger   servers.Logger
+}
+
+// Validate validates CAFileConfig according to predefined rules.
+func (c CAFileConfig) Validate() error {
+	var errs []error
+
+	if c.File == "" {
+		errs = append(errs, errors.New("File can't be empty"))
+	}
+
+	if c.Interval <= 0 {
+		errs = append(errs, errors.New("Interval must be positive"))
+	}
+
+	if c.Logger == nil {
+		errs = append(errs, errors.New("Logger can't be nil"))
+	}
+	return errors.Join(errs...)
+}
+
+// CAFile predetermines the consistency of the pool of CA certificates, which is reloaded from disk,
+// once the file is changed, e.g. to rotate the CAs of the client certificates without restart.
+// Using the methods of the structure, without being initialized by the NewCAFile() constructor, will lead to panic.
+type CAFile struct {
+	*watcher
+	file string
+	pool atomic.Pointer[x509.CertPool]
+}
+
+// Pool returns the last successfully loaded pool.
+func (f *CAFile) Pool() *x509.CertPool {
+	return f.pool.Load()
+}
+
+// load reads the PEM encoded certificates and replaces the pool.
+func (f *CAFile) load() error {
+	data, err := os.ReadFile(f.file)
+	if err != nil {
+		return fmt.Errorf("can't read certificates file: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	if ok := pool.AppendCertsFromPEM(data); !ok {
+		return fmt.Errorf("no valid certificates found in %s", f.file)
+	}
+	f.pool.Store(pool)
+	return nil
+}
+
+// NewCAFile - constructor CAFile.
+func NewCAFile(cfg CAFileConfig) (*CAFile, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	file := &CAFile{file: cfg.File}
+
+	w, err := newWatcher([]string{cfg.File}, file.load, cfg.Logger)
+	if err != nil {
+		return nil, err
+	}
+	file.watcher = w
+
+	go file.watch(cfg.Interval)
+
+	return file, nil
+}
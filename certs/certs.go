@@ -0,0 +1,13 @@
+// Package certs provides sources of certificates, CA pools and revocation status for the TLS options
+// of servers implementations.
+package certs
+
+import (
+	"crypto/tls"
+)
+
+// Provider delivers an interface to a source of server certificates.
+type Provider interface {
+	// GetCertificate returns a certificate for the TLS handshake.
+	GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error)
+}
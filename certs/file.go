@@ -0,0 +1,96 @@
+package certs
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"github.com/golang-mixins/servers"
+	"sync"
+	"time"
+)
+
+// FileConfig delivers a set of settings for File implementation.
+type FileConfig struct {
+	CertFile string
+	KeyFile  string
+	Interval time.Duration
+	Logger   servers.Logger
+}
+
+// Validate validates FileConfig according to predefined rules.
+func (c FileConfig) Validate() error {
+	var errs []error
+
+	if c.CertFile == "" {
+		errs = append(errs, errors.New("CertFile can't be empty"))
+	}
+
+	if c.KeyFile == "" {
+		errs = append(errs, errors.New("KeyFile can't be empty"))
+	}
+
+	if c.Interval <= 0 {
+		errs = append(errs, errors.New("Interval must be positive"))
+	}
+
+	if c.Logger == nil {
+		errs = append(errs, errors.New("Logger can't be nil"))
+	}
+	return errors.Join(errs...)
+}
+
+// File predetermines the consistency of the implementation Provider, which reloads the key pair from disk,
+// once the files are changed, without interrupting the handshakes.
+// Using the methods of the structure, without being initialized by the NewFile() constructor, will lead to panic.
+type File struct {
+	*watcher
+	certFile    string
+	keyFile     string
+	mutex       *sync.RWMutex
+	certificate *tls.Certificate
+}
+
+// GetCertificate returns the last successfully loaded certificate.
+func (f *File) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	return f.certificate, nil
+}
+
+// load reads the key pair and replaces the served certificate.
+func (f *File) load() error {
+	certificate, err := tls.LoadX509KeyPair(f.certFile, f.keyFile)
+	if err != nil {
+		return fmt.Errorf("can't load key pair: %w", err)
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.certificate = &certificate
+	return nil
+}
+
+// NewFile - constructor File.
+func NewFile(cfg FileConfig) (*File, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	file := &File{
+		certFile: cfg.CertFile,
+		keyFile:  cfg.KeyFile,
+		mutex:    new(sync.RWMutex),
+	}
+
+	w, err := newWatcher([]string{cfg.CertFile, cfg.KeyFile}, file.load, cfg.Logger)
+	if err != nil {
+		return nil, err
+	}
+	file.watcher = w
+
+	go file.watch(cfg.Interval)
+
+	return file, nil
+}
@@ -0,0 +1,175 @@
+package certs
+
+import (
+	"bytes"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"github.com/golang-mixins/servers"
+	"golang.org/x/crypto/ocsp"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// maxOCSPResponseBytes bounds the read OCSP response.
+const maxOCSPResponseBytes = 1 << 20
+
+// OCSPConfig delivers a set of settings for OCSP implementation.
+// Timeout bounds each request to the responder (5 seconds by default), Client defaults to http.DefaultClient.
+// FailOpen considers the certificates not revoked, if the responder fails, the failures are logged then.
+type OCSPConfig struct {
+	Client   *http.Client
+	Timeout  time.Duration
+	FailOpen bool
+	Logger   servers.Logger
+}
+
+// Validate validates OCSPConfig according to predefined rules.
+func (c OCSPConfig) Validate() error {
+	var errs []error
+
+	if c.Timeout < 0 {
+		errs = append(errs, errors.New("Timeout can't be negative"))
+	}
+
+	if c.Logger == nil {
+		errs = append(errs, errors.New("Logger can't be nil"))
+	}
+	return errors.Join(errs...)
+}
+
+// ocspStatus is the cached status of the certificate, which is valid until the next update of the responder.
+type ocspStatus struct {
+	err        error
+	nextUpdate time.Time
+}
+
+// OCSP predetermines the consistency of the implementation RevocationChecker, which queries the OCSP responders
+// listed by the certificates, the responses are cached until their next update.
+// The certificates listing no responders are considered not revoked.
+// Using the methods of the structure, without being initialized by the NewOCSP() constructor, will lead to panic.
+type OCSP struct {
+	client   *http.Client
+	timeout  time.Duration
+	failOpen bool
+	logger   servers.Logger
+	mutex    *sync.Mutex
+	cache    map[string]ocspStatus
+}
+
+// CheckRevocation checks the certificate with the first OCSP responder it lists.
+func (o *OCSP) CheckRevocation(cert, issuer *x509.Certificate) error {
+	if len(cert.OCSPServer) == 0 {
+		return nil
+	}
+
+	key := string(issuer.RawSubject) + "/" + cert.SerialNumber.String()
+
+	o.mutex.Lock()
+	status, ok := o.cache[key]
+	o.mutex.Unlock()
+	if ok && time.Now().Before(status.nextUpdate) {
+		return status.err
+	}
+
+	response, err := o.query(cert.OCSPServer[0], cert, issuer)
+	if err != nil {
+		if o.failOpen {
+			o.logger.Error("OCSP error, certificate accepted", "serial", cert.SerialNumber, "error", err)
+			return nil
+		}
+		return err
+	}
+
+	switch response.Status {
+	case ocsp.Good:
+		err = nil
+	case ocsp.Revoked:
+		err = fmt.Errorf("serial %s revoked at %s: %w", cert.SerialNumber, response.RevokedAt, ErrRevoked)
+	default:
+		err = fmt.Errorf("serial %s unknown to the OCSP responder", cert.SerialNumber)
+		if o.failOpen {
+			o.logger.Error("OCSP error, certificate accepted", "error", err)
+			err = nil
+		}
+	}
+
+	if !response.NextUpdate.IsZero() {
+		o.store(key, ocspStatus{err: err, nextUpdate: response.NextUpdate})
+	}
+	return err
+}
+
+// query requests the status of the certificate from the responder.
+func (o *OCSP) query(server string, cert, issuer *x509.Certificate) (*ocsp.Response, error) {
+	request, err := ocsp.CreateRequest(cert, issuer, nil)
+	if err != nil {
+		return nil, fmt.Errorf("can't create OCSP request: %w", err)
+	}
+
+	client := *o.client
+	client.Timeout = o.timeout
+
+	httpResponse, err := client.Post(server, "application/ocsp-request", bytes.NewReader(request))
+	if err != nil {
+		return nil, fmt.Errorf("can't query OCSP responder: %w", err)
+	}
+	defer httpResponse.Body.Close()
+
+	if httpResponse.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("OCSP responder responded %s", httpResponse.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(httpResponse.Body, maxOCSPResponseBytes))
+	if err != nil {
+		return nil, fmt.Errorf("can't read OCSP response: %w", err)
+	}
+
+	response, err := ocsp.ParseResponseForCert(body, cert, issuer)
+	if err != nil {
+		return nil, fmt.Errorf("can't parse OCSP response: %w", err)
+	}
+	return response, nil
+}
+
+// store caches the status, dropping the expired ones.
+func (o *OCSP) store(key string, status ocspStatus) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	now := time.Now()
+	for key, status := range o.cache {
+		if !now.Before(status.nextUpdate) {
+			delete(o.cache, key)
+		}
+	}
+	o.cache[key] = status
+}
+
+// NewOCSP - constructor OCSP.
+func NewOCSP(cfg OCSPConfig) (*OCSP, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	checker := &OCSP{
+		client:   cfg.Client,
+		timeout:  cfg.Timeout,
+		failOpen: cfg.FailOpen,
+		mutex:    new(sync.Mutex),
+		cache:    make(map[string]ocspStatus),
+	}
+
+	checker.logger = cfg.Logger
+
+	if checker.client == nil {
+		checker.client = http.DefaultClient
+	}
+	if checker.timeout == 0 {
+		checker.timeout = 5 * time.Second
+	}
+
+	return checker, nil
+}
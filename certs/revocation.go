@@ -0,0 +1,142 @@
+package certs
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/golang-mixins/servers"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+// ErrRevoked is returned by RevocationChecker for the revoked certificates.
+var ErrRevoked = errors.New("certificate revoked")
+
+// RevocationChecker delivers an interface to a source of the revocation status of certificates.
+type RevocationChecker interface {
+	// CheckRevocation returns an error wrapping ErrRevoked, if the certificate issued by issuer is revoked,
+	// or any other error, if the status can't be determined.
+	CheckRevocation(cert, issuer *x509.Certificate) error
+}
+
+// CheckChain checks the certificates of the verified chain against their issuers, the root is trusted as is.
+func CheckChain(checker RevocationChecker, chain []*x509.Certificate) error {
+	for i := 0; i+1 < len(chain); i++ {
+		if err := checker.CheckRevocation(chain[i], chain[i+1]); err != nil {
+			return fmt.Errorf("%s: %w", chain[i].Subject, err)
+		}
+	}
+	return nil
+}
+
+// CRLFileConfig delivers a set of settings for CRLFile implementation.
+// File holds the PEM encoded CRLs, or a single DER encoded one.
+type CRLFileConfig struct {
+	File     string
+	Interval time.Duration
+	Logger   servers.Logger
+}
+
+// Validate validates CRLFileConfig according to predefined rules.
+func (c CRLFileConfig) Validate() error {
+	var errs []error
+
+	if c.File == "" {
+		errs = append(errs, errors.New("File can't be empty"))
+	}
+
+	if c.Interval <= 0 {
+		errs = append(errs, errors.New("Interval must be positive"))
+	}
+
+	if c.Logger == nil {
+		errs = append(errs, errors.New("Logger can't be nil"))
+	}
+	return errors.Join(errs...)
+}
+
+// CRLFile predetermines the consistency of the implementation RevocationChecker, which checks certificates
+// against the CRLs reloaded from disk, once the file is changed.
+// The certificates of the issuers without CRL are considered not revoked.
+// Using the methods of the structure, without being initialized by the NewCRLFile() constructor, will lead to panic.
+type CRLFile struct {
+	*watcher
+	file string
+	crls atomic.Pointer[[]*x509.RevocationList]
+}
+
+// CheckRevocation checks the certificate against the CRL of issuer, the signature of which is verified.
+// The CRL past its NextUpdate is stale, so the status of the certificates of issuer can't be determined.
+func (f *CRLFile) CheckRevocation(cert, issuer *x509.Certificate) error {
+	for _, crl := range *f.crls.Load() {
+		if !bytes.Equal(crl.RawIssuer, issuer.RawSubject) {
+			continue
+		}
+
+		if err := crl.CheckSignatureFrom(issuer); err != nil {
+			return fmt.Errorf("invalid CRL: %w", err)
+		}
+
+		if !crl.NextUpdate.IsZero() && time.Now().After(crl.NextUpdate) {
+			return fmt.Errorf("CRL of %s is stale since %s", issuer.Subject, crl.NextUpdate)
+		}
+
+		for _, entry := range crl.RevokedCertificateEntries {
+			if entry.SerialNumber.Cmp(cert.SerialNumber) == 0 {
+				return fmt.Errorf("serial %s revoked at %s: %w", cert.SerialNumber, entry.RevocationTime, ErrRevoked)
+			}
+		}
+	}
+	return nil
+}
+
+// load reads the CRLs and replaces the checked ones.
+func (f *CRLFile) load() error {
+	data, err := os.ReadFile(f.file)
+	if err != nil {
+		return fmt.Errorf("can't read CRL file: %w", err)
+	}
+
+	var ders [][]byte
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type == "X509 CRL" {
+			ders = append(ders, block.Bytes)
+		}
+	}
+	if len(ders) == 0 {
+		ders = append(ders, data)
+	}
+
+	crls := make([]*x509.RevocationList, 0, len(ders))
+	for _, der := range ders {
+		crl, err := x509.ParseRevocationList(der)
+		if err != nil {
+			return fmt.Errorf("can't parse CRL: %w", err)
+		}
+		crls = append(crls, crl)
+	}
+	f.crls.Store(&crls)
+	return nil
+}
+
+// NewCRLFile - constructor CRLFile.
+func NewCRLFile(cfg CRLFileConfig) (*CRLFile, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	file := &CRLFile{file: cfg.File}
+
+	w, err := newWatcher([]string{cfg.File}, file.load, cfg.Logger)
+	if err != nil {
+		return nil, err
+	}
+	file.watcher = w
+
+	go file.watch(cfg.Interval)
+
+	return file, nil
+}
@@ -0,0 +1,109 @@
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"github.com/golang-mixins/servers"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// issue returns the certificate of the serial signed by the issuer, or the self-signed one, if the issuer is nil.
+func issue(t *testing.T, serial int64, issuer *x509.Certificate, issuerKey *ecdsa.PrivateKey) (
+	*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("can't generate key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(serial),
+		Subject:               pkix.Name{CommonName: "serial " + big.NewInt(serial).String()},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  issuer == nil,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+	}
+	if issuer == nil {
+		issuer, issuerKey = template, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, issuer, &key.PublicKey, issuerKey)
+	if err != nil {
+		t.Fatalf("can't create certificate: %v", err)
+	}
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("can't parse certificate: %v", err)
+	}
+	return certificate, key
+}
+
+func TestCRLFileCheckRevocation(t *testing.T) {
+	ca, caKey := issue(t, 1, nil, nil)
+	revoked, _ := issue(t, 2, ca, caKey)
+	valid, _ := issue(t, 3, ca, caKey)
+
+	tests := []struct {
+		name       string
+		nextUpdate time.Time
+		cert       *x509.Certificate
+		wantErr    bool
+		wantRevoke bool
+	}{
+		{name: "revoked", nextUpdate: time.Now().Add(time.Hour), cert: revoked, wantErr: true, wantRevoke: true},
+		{name: "valid", nextUpdate: time.Now().Add(time.Hour), cert: valid},
+		{name: "stale", nextUpdate: time.Now().Add(-time.Minute), cert: valid, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			der, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+				Number:     big.NewInt(1),
+				ThisUpdate: time.Now().Add(-time.Hour),
+				NextUpdate: test.nextUpdate,
+				RevokedCertificateEntries: []x509.RevocationListEntry{
+					{SerialNumber: revoked.SerialNumber, RevocationTime: time.Now().Add(-time.Hour)},
+				},
+			}, ca, caKey)
+			if err != nil {
+				t.Fatalf("can't create CRL: %v", err)
+			}
+
+			file := filepath.Join(t.TempDir(), "crl.pem")
+			if err = os.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: der}), 0o600); err != nil {
+				t.Fatalf("can't write CRL: %v", err)
+			}
+
+			checker, err := NewCRLFile(CRLFileConfig{
+				File:     file,
+				Interval: time.Hour,
+				Logger:   servers.NewLogger(io.Discard, ""),
+			})
+			if err != nil {
+				t.Fatalf("NewCRLFile: %v", err)
+			}
+			defer checker.Close()
+
+			err = checker.CheckRevocation(test.cert, ca)
+			if test.wantErr != (err != nil) {
+				t.Fatalf("CheckRevocation: got %v, want error %t", err, test.wantErr)
+			}
+			if test.wantRevoke != errors.Is(err, ErrRevoked) {
+				t.Fatalf("CheckRevocation: got %v, want ErrRevoked %t", err, test.wantRevoke)
+			}
+		})
+	}
+}
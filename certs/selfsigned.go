@@ -0,0 +1,89 @@
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"math/big"
+	"net"
+	"time"
+)
+
+// selfSignedValidity is the lifetime of the self-signed certificate.
+const selfSignedValidity = 30 * 24 * time.Hour
+
+// SelfSigned predetermines the consistency of the implementation Provider, which serves the self-signed
+// certificate generated in memory, meant for the local development only.
+// Using the methods of the structure, without being initialized by the NewSelfSigned() constructor, will lead to panic.
+type SelfSigned struct {
+	certificate *tls.Certificate
+}
+
+// GetCertificate returns the generated certificate.
+func (s *SelfSigned) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return s.certificate, nil
+}
+
+// Pool returns the pool trusting the generated certificate, e.g. for the clients in tests.
+func (s *SelfSigned) Pool() *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(s.certificate.Leaf)
+	return pool
+}
+
+// NewSelfSigned - constructor SelfSigned.
+// The certificate is valid for the hosts (names or IPs), localhost, 127.0.0.1 and ::1 by default, for 30 days.
+func NewSelfSigned(hosts ...string) (*SelfSigned, error) {
+	if len(hosts) == 0 {
+		hosts = []string{"localhost", "127.0.0.1", "::1"}
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("can't generate key: %w", err)
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("can't generate serial number: %w", err)
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{Organization: []string{"servers development"}, CommonName: hosts[0]},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(selfSignedValidity),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, fmt.Errorf("can't create certificate: %w", err)
+	}
+
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, fmt.Errorf("can't parse certificate: %w", err)
+	}
+
+	return &SelfSigned{certificate: &tls.Certificate{
+		Certificate: [][]byte{der},
+		PrivateKey:  key,
+		Leaf:        leaf,
+	}}, nil
+}
@@ -0,0 +1,78 @@
+package certs
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// SNI predetermines the consistency of the implementation Provider, which selects the provider of the certificate
+// by the server name of the handshake, so that one server terminates TLS for several domains.
+// Using the methods of the structure, without being initialized by the NewSNI() constructor, will lead to panic.
+type SNI struct {
+	providers map[string]Provider
+	fallback  Provider
+}
+
+// GetCertificate returns the certificate of the provider matching the server name exactly, or by the wildcard
+// (e.g. *.example.com matches a.example.com, but neither example.com nor a.b.example.com), or of the fallback.
+func (s *SNI) GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	name := strings.ToLower(strings.TrimSuffix(hello.ServerName, "."))
+
+	if provider, ok := s.providers[name]; ok {
+		return provider.GetCertificate(hello)
+	}
+
+	if _, parent, ok := strings.Cut(name, "."); ok {
+		if provider, ok := s.providers["*."+parent]; ok {
+			return provider.GetCertificate(hello)
+		}
+	}
+
+	if s.fallback == nil {
+		return nil, fmt.Errorf("no certificate for server name %q", hello.ServerName)
+	}
+	return s.fallback.GetCertificate(hello)
+}
+
+// validateServerName validates the server name, which is either a host name or a wildcard of one label.
+func validateServerName(name string) error {
+	host := strings.TrimPrefix(name, "*.")
+	if host == "" || strings.ContainsAny(host, "*:/ ") {
+		return fmt.Errorf("invalid server name %q", name)
+	}
+	return nil
+}
+
+// NewSNI - constructor SNI.
+// The providers are keyed by the server names, fallback, if set, serves the handshakes matching none of them,
+// including the ones without the server name.
+func NewSNI(providers map[string]Provider, fallback Provider) (*SNI, error) {
+	var errs []error
+
+	if len(providers) == 0 {
+		errs = append(errs, errors.New("providers can't be empty"))
+	}
+
+	sni := &SNI{
+		providers: make(map[string]Provider, len(providers)),
+		fallback:  fallback,
+	}
+	for name, provider := range providers {
+		if err := validateServerName(name); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if provider == nil {
+			errs = append(errs, fmt.Errorf("provider of %q can't be nil", name))
+			continue
+		}
+		sni.providers[strings.ToLower(strings.TrimSuffix(name, "."))] = provider
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return sni, nil
+}
@@ -0,0 +1,92 @@
+// Package spiffe provides the certificates of the SPIFFE workload identity for the TLS options
+// of servers implementations, taken from the SPIFFE Workload API (e.g. SPIRE agent) and rotated by it.
+package spiffe
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"github.com/spiffe/go-spiffe/v2/spiffetls/tlsconfig"
+	"github.com/spiffe/go-spiffe/v2/workloadapi"
+)
+
+// Config delivers a set of settings for Source implementation.
+// Addr is the address of the Workload API (e.g. "unix:///run/spire/agent.sock"), SPIFFE_ENDPOINT_SOCKET is used,
+// if it is empty. Authorizer authorizes the SPIFFE IDs of the clients, e.g. tlsconfig.AuthorizeMemberOf,
+// it must be set explicitly, tlsconfig.AuthorizeAny included.
+type Config struct {
+	Addr       string
+	Authorizer tlsconfig.Authorizer
+}
+
+// Validate validates Config according to predefined rules.
+func (c Config) Validate() error {
+	var errs []error
+
+	if c.Authorizer == nil {
+		errs = append(errs, errors.New("Authorizer can't be nil"))
+	}
+	return errors.Join(errs...)
+}
+
+// Source predetermines the consistency of the implementation certs.Provider, which serves the X.509 SVID
+// of the workload and verifies the clients against the trust bundles, both kept up to date by the Workload API.
+// Using the methods of the structure, without being initialized by the New() constructor, will lead to panic.
+type Source struct {
+	source     *workloadapi.X509Source
+	authorizer tlsconfig.Authorizer
+	get        func(*tls.ClientHelloInfo) (*tls.Certificate, error)
+	verify     func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error
+}
+
+// GetCertificate returns the current X.509 SVID of the workload.
+func (s *Source) GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return s.get(hello)
+}
+
+// VerifyPeerCertificate verifies the X.509 SVID of the client against the current trust bundles and authorizes it.
+// It is meant for VerifyPeerCertificate of the TLS options together with tls.RequireAnyClientCert,
+// since the certificates are verified by it instead of crypto/tls.
+func (s *Source) VerifyPeerCertificate(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
+	return s.verify(rawCerts, verifiedChains)
+}
+
+// ServerTLSConfig returns tls.Config for mutual TLS authentication, e.g. for the gRPC and HTTP/3 servers.
+func (s *Source) ServerTLSConfig() *tls.Config {
+	return tlsconfig.MTLSServerConfig(s.source, s.source, s.authorizer)
+}
+
+// Close closes the connection to the Workload API.
+func (s *Source) Close() error {
+	if err := s.source.Close(); err != nil {
+		return fmt.Errorf("can't close X.509 source: %w", err)
+	}
+	return nil
+}
+
+// New - constructor Source.
+// It blocks until the first X.509 SVID is received, or ctx is done.
+func New(ctx context.Context, cfg Config) (*Source, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	var options []workloadapi.X509SourceOption
+	if cfg.Addr != "" {
+		options = append(options, workloadapi.WithClientOptions(workloadapi.WithAddr(cfg.Addr)))
+	}
+
+	source, err := workloadapi.NewX509Source(ctx, options...)
+	if err != nil {
+		return nil, fmt.Errorf("can't create X.509 source: %w", err)
+	}
+
+	return &Source{
+		source:     source,
+		authorizer: cfg.Authorizer,
+		get:        tlsconfig.GetCertificate(source),
+		verify:     tlsconfig.VerifyPeerCertificate(source, cfg.Authorizer),
+	}, nil
+}
@@ -0,0 +1,222 @@
+// Package vault provides the certificates issued by the PKI secrets engine of HashiCorp Vault for the TLS options
+// of servers implementations, which are renewed before they expire.
+package vault
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"github.com/golang-mixins/servers"
+	"github.com/hashicorp/vault/api"
+	"math/rand/v2"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Config delivers a set of settings for Provider implementation.
+// Client is the authenticated Vault client, the certificates are issued by Role of the engine mounted at Mount
+// ("pki" by default) for CommonName, AltNames and IPSANs, TTL defaults to the one of Role.
+// The certificate is renewed once two thirds of its lifetime have passed, brought forward by the random part
+// of Jitter (10% of the delay by default) so that a fleet doesn't renew at once. The failed renewals are retried
+// every RetryInterval (30 seconds by default), while the current certificate is served until it expires.
+// OnError, if set, is called on each failed renewal, e.g. to alert before the certificate expires.
+type Config struct {
+	Client        *api.Client
+	Mount         string
+	Role          string
+	CommonName    string
+	AltNames      []string
+	IPSANs        []string
+	TTL           time.Duration
+	Jitter        float64
+	RetryInterval time.Duration
+	OnError       func(err error)
+	Logger        servers.Logger
+}
+
+// Validate validates Config according to predefined rules.
+func (c Config) Validate() error {
+	var errs []error
+
+	if c.Client == nil {
+		errs = append(errs, errors.New("Client can't be nil"))
+	}
+
+	if c.Role == "" {
+		errs = append(errs, errors.New("Role can't be empty"))
+	}
+
+	if c.CommonName == "" {
+		errs = append(errs, errors.New("CommonName can't be empty"))
+	}
+
+	if c.TTL < 0 {
+		errs = append(errs, errors.New("TTL can't be negative"))
+	}
+
+	if c.Jitter < 0 || c.Jitter >= 1 {
+		errs = append(errs, errors.New("Jitter must be between 0 and 1"))
+	}
+
+	if c.RetryInterval < 0 {
+		errs = append(errs, errors.New("RetryInterval can't be negative"))
+	}
+
+	if c.Logger == nil {
+		errs = append(errs, errors.New("Logger can't be nil"))
+	}
+	return errors.Join(errs...)
+}
+
+// Provider predetermines the consistency of the implementation certs.Provider, which serves the certificate
+// issued by Vault and renews it in the background.
+// Using the methods of the structure, without being initialized by the New() constructor, will lead to panic.
+type Provider struct {
+	client        *api.Client
+	path          string
+	data          map[string]interface{}
+	jitter        float64
+	retryInterval time.Duration
+	onError       func(err error)
+	logger        servers.Logger
+	certificate   atomic.Pointer[tls.Certificate]
+	cancel        context.CancelFunc
+	closeOnce     *sync.Once
+}
+
+// GetCertificate returns the last issued certificate.
+func (p *Provider) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return p.certificate.Load(), nil
+}
+
+// Close stops the renewal.
+func (p *Provider) Close() error {
+	p.closeOnce.Do(p.cancel)
+	return nil
+}
+
+// issue requests the new certificate and replaces the served one.
+func (p *Provider) issue(ctx context.Context) error {
+	secret, err := p.client.Logical().WriteWithContext(ctx, p.path, p.data)
+	if err != nil {
+		return fmt.Errorf("can't issue certificate: %w", err)
+	}
+	if secret == nil || secret.Data == nil {
+		return errors.New("can't issue certificate: empty response")
+	}
+
+	certificatePEM, _ := secret.Data["certificate"].(string)
+	keyPEM, _ := secret.Data["private_key"].(string)
+
+	chain := []string{certificatePEM}
+	if caChain, ok := secret.Data["ca_chain"].([]interface{}); ok {
+		for _, ca := range caChain {
+			if ca, ok := ca.(string); ok {
+				chain = append(chain, ca)
+			}
+		}
+	} else if issuingCA, ok := secret.Data["issuing_ca"].(string); ok {
+		chain = append(chain, issuingCA)
+	}
+
+	certificate, err := tls.X509KeyPair([]byte(strings.Join(chain, "\n")), []byte(keyPEM))
+	if err != nil {
+		return fmt.Errorf("can't parse issued certificate: %w", err)
+	}
+
+	p.certificate.Store(&certificate)
+	p.logger.Info("certificate issued", "serial", certificate.Leaf.SerialNumber, "not_after", certificate.Leaf.NotAfter)
+	return nil
+}
+
+// renewDelay returns the delay until the renewal of the current certificate.
+func (p *Provider) renewDelay() time.Duration {
+	leaf := p.certificate.Load().Leaf
+	delay := leaf.NotAfter.Sub(leaf.NotBefore) * 2 / 3
+	delay -= time.Duration(rand.Float64() * p.jitter * float64(delay))
+	return time.Until(leaf.NotBefore.Add(delay))
+}
+
+// renew renews the certificate, until ctx is done.
+func (p *Provider) renew(ctx context.Context) {
+	timer := time.NewTimer(p.renewDelay())
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+			if err := p.issue(ctx); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+
+				p.logger.Error("renewal error", "error", err)
+				if p.onError != nil {
+					p.onError(err)
+				}
+				timer.Reset(p.retryInterval)
+				continue
+			}
+			timer.Reset(p.renewDelay())
+		}
+	}
+}
+
+// New - constructor Provider.
+// The first certificate is issued synchronously, the error is returned, if it fails.
+func New(cfg Config) (*Provider, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	mount := strings.Trim(cfg.Mount, "/")
+	if mount == "" {
+		mount = "pki"
+	}
+
+	data := map[string]interface{}{"common_name": cfg.CommonName}
+	if len(cfg.AltNames) != 0 {
+		data["alt_names"] = strings.Join(cfg.AltNames, ",")
+	}
+	if len(cfg.IPSANs) != 0 {
+		data["ip_sans"] = strings.Join(cfg.IPSANs, ",")
+	}
+	if cfg.TTL != 0 {
+		data["ttl"] = cfg.TTL.String()
+	}
+
+	provider := &Provider{
+		client:        cfg.Client,
+		path:          mount + "/issue/" + cfg.Role,
+		data:          data,
+		jitter:        cfg.Jitter,
+		retryInterval: cfg.RetryInterval,
+		onError:       cfg.OnError,
+		closeOnce:     new(sync.Once),
+	}
+
+	provider.logger = cfg.Logger
+
+	if provider.jitter == 0 {
+		provider.jitter = 0.1
+	}
+	if provider.retryInterval == 0 {
+		provider.retryInterval = 30 * time.Second
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := provider.issue(ctx); err != nil {
+		cancel()
+		return nil, err
+	}
+	provider.cancel = cancel
+
+	go provider.renew(ctx)
+
+	return provider, nil
+}
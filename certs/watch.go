@@ -0,0 +1,96 @@
+package certs
+
+import (
+	"fmt"
+	"github.com/golang-mixins/servers"
+	"os"
+	"sync"
+	"time"
+)
+
+// watcher periodically checks the files and calls load once they are changed.
+type watcher struct {
+	files     []string
+	modified  time.Time
+	load      func() error
+	logger    servers.Logger
+	done      chan struct{}
+	closeOnce *sync.Once
+}
+
+// newWatcher calls load once and returns the watcher of the files, which is started by watch.
+func newWatcher(files []string, load func() error, logger servers.Logger) (*watcher, error) {
+	w := &watcher{
+		files:     files,
+		load:      load,
+		logger:    logger,
+		done:      make(chan struct{}),
+		closeOnce: new(sync.Once),
+	}
+
+	modified, err := w.lastModified()
+	if err != nil {
+		return nil, err
+	}
+
+	if err = load(); err != nil {
+		return nil, err
+	}
+	w.modified = modified
+
+	return w, nil
+}
+
+// Close stops watching the files.
+func (w *watcher) Close() error {
+	w.closeOnce.Do(func() {
+		close(w.done)
+	})
+	return nil
+}
+
+// watch checks the files every interval, until the watcher is closed.
+func (w *watcher) watch(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+			modified, err := w.lastModified()
+			if err != nil {
+				w.logger.Error("stat error", "error", err)
+				continue
+			}
+
+			if !modified.After(w.modified) {
+				continue
+			}
+
+			if err = w.load(); err != nil {
+				w.logger.Error("reload error", "error", err)
+				continue
+			}
+			w.modified = modified
+			w.logger.Info("file reloaded", "files", w.files)
+		}
+	}
+}
+
+// lastModified returns the latest modification time among the files.
+func (w *watcher) lastModified() (time.Time, error) {
+	var modified time.Time
+	for _, file := range w.files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("can't stat %s: %w", file, err)
+		}
+
+		if info.ModTime().After(modified) {
+			modified = info.ModTime()
+		}
+	}
+	return modified, nil
+}
@@ -0,0 +1,207 @@
+// Package chaos provides the decorators of servers implementations, which inject the faults (delayed startup,
+// random exit of Serve, slow Stop and connection resets) to test the supervisors and the deploy tooling
+// against the failure modes.
+package chaos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golang-mixins/servers"
+	"math/rand/v2"
+	"net"
+	"sync"
+	"time"
+)
+
+// ErrInjected is returned by Serve, which exits by the injected fault.
+var ErrInjected = errors.New("injected fault")
+
+// Config delivers a set of settings for the faults.
+// StartupDelay delays Serve of the launcher.
+// ExitProbability is the probability of each Serve to exit with ErrInjected at a random moment within ExitAfter,
+// the launcher is stopped then.
+// StopDelay delays Stop of the launcher, unless ctx is done earlier.
+// ResetProbability is the probability of each connection accepted by Listener to be reset at a random moment
+// within ResetAfter (at once, if ResetAfter is zero).
+type Config struct {
+	StartupDelay     time.Duration
+	ExitProbability  float64
+	ExitAfter        time.Duration
+	StopDelay        time.Duration
+	ResetProbability float64
+	ResetAfter       time.Duration
+}
+
+// Validate validates Config according to predefined rules.
+func (c Config) Validate() error {
+	var errs []error
+
+	if c.StartupDelay < 0 {
+		errs = append(errs, errors.New("StartupDelay can't be negative"))
+	}
+
+	if c.ExitProbability < 0 || c.ExitProbability > 1 {
+		errs = append(errs, errors.New("ExitProbability must be between 0 and 1"))
+	}
+
+	if c.ExitProbability > 0 && c.ExitAfter <= 0 {
+		errs = append(errs, errors.New("ExitAfter must be positive, if ExitProbability is set"))
+	}
+
+	if c.StopDelay < 0 {
+		errs = append(errs, errors.New("StopDelay can't be negative"))
+	}
+
+	if c.ResetProbability < 0 || c.ResetProbability > 1 {
+		errs = append(errs, errors.New("ResetProbability must be between 0 and 1"))
+	}
+
+	if c.ResetAfter < 0 {
+		errs = append(errs, errors.New("ResetAfter can't be negative"))
+	}
+	return errors.Join(errs...)
+}
+
+// happens reports whether the fault of the probability happens.
+func happens(probability float64) bool {
+	return probability > 0 && rand.Float64() < probability
+}
+
+// within returns the random duration within the limit.
+func within(limit time.Duration) time.Duration {
+	if limit <= 0 {
+		return 0
+	}
+	return rand.N(limit)
+}
+
+// Launcher predetermines the consistency of the implementation servers.Launcher, which injects the faults
+// into the decorated launcher.
+// Using the methods of the structure, without being initialized by the Wrap() constructor, will lead to panic.
+type Launcher struct {
+	launcher servers.Launcher
+	config   Config
+	mutex    *sync.Mutex
+	stopped  bool
+	stopping chan struct{}
+}
+
+// Serve serving the launcher after StartupDelay, it may exit with ErrInjected.
+func (l *Launcher) Serve() error {
+	if l.config.StartupDelay > 0 {
+		timer := time.NewTimer(l.config.StartupDelay)
+		select {
+		case <-timer.C:
+		case <-l.stopping:
+			timer.Stop()
+		}
+	}
+
+	serving := make(chan error, 1)
+	go func() {
+		serving <- l.launcher.Serve()
+	}()
+
+	if !happens(l.config.ExitProbability) {
+		return <-serving
+	}
+
+	timer := time.NewTimer(within(l.config.ExitAfter))
+	defer timer.Stop()
+
+	select {
+	case err := <-serving:
+		return err
+	case <-l.stopping:
+		return <-serving
+	case <-timer.C:
+	}
+
+	if err := l.launcher.Stop(context.Background()); err != nil && !errors.Is(err, servers.ErrAlreadyStopped) {
+		<-serving
+		return fmt.Errorf("chaos exit: %w", errors.Join(ErrInjected, err))
+	}
+	<-serving
+	return fmt.Errorf("chaos exit: %w", ErrInjected)
+}
+
+// Stop stops the launcher after StopDelay or once ctx is done, whichever is earlier.
+func (l *Launcher) Stop(ctx context.Context) error {
+	l.mutex.Lock()
+	if !l.stopped {
+		l.stopped = true
+		close(l.stopping)
+	}
+	l.mutex.Unlock()
+
+	if l.config.StopDelay > 0 {
+		timer := time.NewTimer(l.config.StopDelay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+		}
+	}
+
+	return l.launcher.Stop(ctx)
+}
+
+// Unwrap returns the decorated launcher.
+func (l *Launcher) Unwrap() servers.Launcher {
+	return l.launcher
+}
+
+// Wrap - constructor Launcher decorating the launcher.
+func Wrap(launcher servers.Launcher, cfg Config) (*Launcher, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &Launcher{
+		launcher: launcher,
+		config:   cfg,
+		mutex:    new(sync.Mutex),
+		stopping: make(chan struct{}),
+	}, nil
+}
+
+// listener predetermines the consistency of the implementation net.Listener, which resets the accepted connections.
+type listener struct {
+	net.Listener
+	config Config
+}
+
+// Accept waits for and returns the next connection, which may be reset.
+func (l listener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil || !happens(l.config.ResetProbability) {
+		return conn, err
+	}
+
+	time.AfterFunc(within(l.config.ResetAfter), func() {
+		reset(conn)
+	})
+	return conn, nil
+}
+
+// reset closes the connection with RST instead of FIN, if it is TCP.
+func reset(conn net.Conn) {
+	if tcp, ok := conn.(*net.TCPConn); ok {
+		tcp.SetLinger(0)
+	}
+	conn.Close()
+}
+
+// Listener - constructor net.Listener, which resets the accepted connections with ResetProbability,
+// e.g. to be served by the standard server (see Config.Listener of http/std).
+func Listener(l net.Listener, cfg Config) (net.Listener, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return listener{
+		Listener: l,
+		config:   cfg,
+	}, nil
+}
@@ -0,0 +1,32 @@
+package servers
+
+import (
+	"context"
+	"errors"
+	"golang.org/x/sync/errgroup"
+)
+
+// ServeGroup registers serving the launcher in the errgroup together with stopping it once ctx is done,
+// e.g. ctx of errgroup.WithContext, which is canceled by the first error of the group.
+// The launcher exited without error doesn't stop the rest of the group, ErrAlreadyStopped isn't reported.
+func ServeGroup(ctx context.Context, g *errgroup.Group, launcher Launcher) {
+	serving := make(chan struct{})
+	g.Go(func() error {
+		defer close(serving)
+		return launcher.Serve()
+	})
+
+	g.Go(func() error {
+		select {
+		case <-ctx.Done():
+		case <-serving:
+			return nil
+		}
+
+		err := launcher.Stop(context.WithoutCancel(ctx))
+		if errors.Is(err, ErrAlreadyStopped) {
+			return nil
+		}
+		return err
+	})
+}
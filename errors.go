@@ -0,0 +1,36 @@
+package servers
+
+import (
+	"errors"
+)
+
+// The errors returned by the launchers wrap the sentinel errors below, so that the failure modes are told apart
+// by errors.Is.
+var (
+	// ErrAlreadyStopped is returned by Stop, once the launcher is already stopped (or stopping).
+	ErrAlreadyStopped = errors.New("already stopped")
+	// ErrShutdownTimeout is returned by Stop, once the graceful shutdown doesn't complete in time
+	// and the connections or calls are cut.
+	ErrShutdownTimeout = errors.New("shutdown timeout exceeded")
+	// ErrBindFailed is returned by Serve (or Listen), once the listener can't be bound.
+	ErrBindFailed = errors.New("bind failed")
+	// ErrServerClosed is returned by Serve (or Listen), once it is called after Stop.
+	ErrServerClosed = errors.New("server closed")
+)
+
+// BindError is returned by Serve (or Listen), once the listener can't be bound on Addr,
+// it matches ErrBindFailed and the underlying error (e.g. syscall.EADDRINUSE) by errors.Is.
+type BindError struct {
+	Addr string
+	Err  error
+}
+
+// Error returns the message of the error.
+func (e *BindError) Error() string {
+	return "can't bind " + e.Addr + ": " + e.Err.Error()
+}
+
+// Unwrap returns ErrBindFailed and the underlying error.
+func (e *BindError) Unwrap() []error {
+	return []error{ErrBindFailed, e.Err}
+}
@@ -0,0 +1,24 @@
+package servers
+
+import (
+	"context"
+)
+
+// LauncherFunc adapts the pair of functions to Launcher. Nil StopFunc stops nothing.
+type LauncherFunc struct {
+	ServeFunc func() error
+	StopFunc  func(ctx context.Context) error
+}
+
+// Serve serving the launcher.
+func (f LauncherFunc) Serve() error {
+	return f.ServeFunc()
+}
+
+// Stop stops the launcher.
+func (f LauncherFunc) Stop(ctx context.Context) error {
+	if f.StopFunc == nil {
+		return nil
+	}
+	return f.StopFunc(ctx)
+}
@@ -0,0 +1,203 @@
+package servers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// Group predetermines the consistency of the implementation Launcher, which runs several launchers together.
+// Launchers are started by stages of the same order (see WithOrder): the next stage is started once all the
+// launchers of the previous one are ready. Stages are stopped in the reverse order.
+// Using the methods of the structure, without being initialized by the NewGroup() constructor, will lead to panic.
+type Group struct {
+	stages    [][]member
+	count     int
+	mutex     *sync.Mutex
+	stopping  chan struct{}
+	stopped   chan struct{}
+	closeOnce *sync.Once
+}
+
+// member is a launcher of Group together with its position in the constructor arguments.
+type member struct {
+	Launcher
+	index int
+}
+
+// String describes the launcher in errors by its name, if it is Named, otherwise by its position.
+func (m member) String() string {
+	if name := NameOf(m.Launcher); name != "" {
+		return name
+	}
+	return "launcher " + strconv.Itoa(m.index)
+}
+
+// Serve serves all the launchers.
+// Once any of them exits, the rest are stopped. The errors of all the launchers are returned joined.
+// No stage is started once Stop is called, ErrServerClosed of the launchers stopped by Stop isn't reported.
+func (g *Group) Serve() error {
+	if g.count == 0 {
+		return nil
+	}
+
+	type result struct {
+		member member
+		err    error
+	}
+
+	results := make(chan result, g.count)
+	started := 0
+
+	var first *result
+starting:
+	for _, stage := range g.stages {
+		g.mutex.Lock()
+		select {
+		case <-g.stopping:
+			g.mutex.Unlock()
+			break starting
+		default:
+		}
+		for _, launcher := range stage {
+			go func(launcher member) {
+				results <- result{member: launcher, err: launcher.Serve()}
+			}(launcher)
+			started++
+		}
+		g.mutex.Unlock()
+
+		for _, launcher := range stage {
+			select {
+			case <-ready(launcher.Launcher):
+			case result := <-results:
+				first = &result
+				break starting
+			case <-g.stopping:
+				break starting
+			}
+		}
+	}
+
+	if first == nil && started != 0 {
+		select {
+		case result := <-results:
+			first = &result
+		case <-g.stopping:
+		}
+	}
+
+	serveErr := func(result result) error {
+		if result.err == nil {
+			return nil
+		}
+		if errors.Is(result.err, ErrServerClosed) && g.isStopping() {
+			return nil
+		}
+		return fmt.Errorf("%s serve: %w", result.member, result.err)
+	}
+
+	errs := make([]error, 0, started+1)
+	received := 0
+	if first != nil {
+		received++
+		errs = append(errs, serveErr(*first))
+
+		if err := g.Stop(context.Background()); err != nil && !errors.Is(err, ErrAlreadyStopped) {
+			errs = append(errs, err)
+		}
+	}
+
+	for ; received < started; received++ {
+		errs = append(errs, serveErr(<-results))
+	}
+
+	return errors.Join(errs...)
+}
+
+// isStopping reports whether Stop has been called.
+func (g *Group) isStopping() bool {
+	select {
+	case <-g.stopping:
+		return true
+	default:
+		return false
+	}
+}
+
+// Stop stops the stages in the reverse order, the launchers of the same stage are stopped concurrently.
+// The errors of all the launchers are returned joined, except ErrAlreadyStopped.
+// The stages are stopped once: Stop called again waits for the first call to finish (or ctx to be done)
+// and returns ErrAlreadyStopped.
+func (g *Group) Stop(ctx context.Context) error {
+	first := false
+	g.closeOnce.Do(func() {
+		g.mutex.Lock()
+		close(g.stopping)
+		g.mutex.Unlock()
+		first = true
+	})
+
+	if !first {
+		select {
+		case <-g.stopped:
+		case <-ctx.Done():
+		}
+		return ErrAlreadyStopped
+	}
+	defer close(g.stopped)
+
+	var errs []error
+	for i := len(g.stages) - 1; i >= 0; i-- {
+		stage := g.stages[i]
+		stageErrs := make([]error, len(stage))
+
+		wg := new(sync.WaitGroup)
+		wg.Add(len(stage))
+		for j, launcher := range stage {
+			go func(j int, launcher member) {
+				defer wg.Done()
+				if err := launcher.Stop(ctx); err != nil && !errors.Is(err, ErrAlreadyStopped) {
+					stageErrs[j] = fmt.Errorf("%s stop: %w", launcher, err)
+				}
+			}(j, launcher)
+		}
+		wg.Wait()
+
+		errs = append(errs, stageErrs...)
+	}
+
+	return errors.Join(errs...)
+}
+
+// NewGroup - constructor Group.
+func NewGroup(launchers ...Launcher) *Group {
+	members := make([]member, 0, len(launchers))
+	for i, launcher := range launchers {
+		members = append(members, member{Launcher: launcher, index: i})
+	}
+
+	sort.SliceStable(members, func(i, j int) bool {
+		return orderOf(members[i].Launcher) < orderOf(members[j].Launcher)
+	})
+
+	var stages [][]member
+	for i, launcher := range members {
+		if i == 0 || orderOf(launcher.Launcher) != orderOf(members[i-1].Launcher) {
+			stages = append(stages, nil)
+		}
+		stages[len(stages)-1] = append(stages[len(stages)-1], launcher)
+	}
+
+	return &Group{
+		stages:    stages,
+		count:     len(launchers),
+		mutex:     new(sync.Mutex),
+		stopping:  make(chan struct{}),
+		stopped:   make(chan struct{}),
+		closeOnce: new(sync.Once),
+	}
+}
@@ -0,0 +1,120 @@
+package servers_test
+
+import (
+	"context"
+	"errors"
+	"github.com/golang-mixins/servers"
+	"github.com/golang-mixins/servers/mock"
+	"testing"
+	"time"
+)
+
+func newMock(t *testing.T, cfg mock.Config) *mock.Launcher {
+	t.Helper()
+
+	launcher, err := mock.New(cfg)
+	if err != nil {
+		t.Fatalf("can't create mock: %v", err)
+	}
+	return launcher
+}
+
+func stopCall(t *testing.T, launcher *mock.Launcher) mock.Call {
+	t.Helper()
+
+	for _, call := range launcher.Calls() {
+		if call.Method == mock.MethodStop {
+			return call
+		}
+	}
+	t.Fatal("launcher isn't stopped")
+	return mock.Call{}
+}
+
+func TestGroupStopsStagesInReverseOrder(t *testing.T) {
+	ingress := newMock(t, mock.Config{StopDelay: 20 * time.Millisecond})
+	backend := newMock(t, mock.Config{StopDelay: 20 * time.Millisecond})
+	database := newMock(t, mock.Config{})
+
+	group := servers.NewGroup(
+		servers.WithOrder(ingress, 2),
+		servers.WithOrder(database, 0),
+		servers.WithOrder(backend, 1),
+	)
+
+	serving := make(chan error, 1)
+	go func() {
+		serving <- group.Serve()
+	}()
+	<-ingress.Ready()
+
+	if err := group.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	if err := <-serving; err != nil {
+		t.Fatalf("Serve: %v", err)
+	}
+
+	ingressStop, backendStop, databaseStop := stopCall(t, ingress), stopCall(t, backend), stopCall(t, database)
+	if !ingressStop.Started.Before(backendStop.Started) || !backendStop.Started.Before(databaseStop.Started) {
+		t.Fatalf("stages aren't stopped in reverse order: ingress %s, backend %s, database %s",
+			ingressStop.Started, backendStop.Started, databaseStop.Started)
+	}
+}
+
+func TestGroupStopsOnce(t *testing.T) {
+	launchers := []*mock.Launcher{newMock(t, mock.Config{}), newMock(t, mock.Config{})}
+	group := servers.NewGroup(launchers[0], launchers[1])
+
+	serving := make(chan error, 1)
+	go func() {
+		serving <- group.Serve()
+	}()
+	<-launchers[0].Ready()
+	<-launchers[1].Ready()
+
+	if err := group.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	if err := group.Stop(context.Background()); !errors.Is(err, servers.ErrAlreadyStopped) {
+		t.Fatalf("second Stop: got %v, want ErrAlreadyStopped", err)
+	}
+	if err := <-serving; err != nil {
+		t.Fatalf("Serve: %v", err)
+	}
+
+	for i, launcher := range launchers {
+		if calls := launcher.CallsOf(mock.MethodStop); calls != 1 {
+			t.Errorf("launcher %d is stopped %d times", i, calls)
+		}
+	}
+}
+
+func TestGroupStopsRestOnFailure(t *testing.T) {
+	errFailed := errors.New("failed")
+	failing := newMock(t, mock.Config{ServeErrors: []error{errFailed}, ServeDelay: 10 * time.Millisecond})
+	healthy := newMock(t, mock.Config{})
+
+	err := servers.NewGroup(healthy, failing).Serve()
+	if !errors.Is(err, errFailed) {
+		t.Fatalf("Serve: got %v, want %v", err, errFailed)
+	}
+	if !healthy.Stopped() {
+		t.Fatal("healthy launcher isn't stopped")
+	}
+}
+
+func TestGroupDoesNotStartAfterStop(t *testing.T) {
+	launcher := newMock(t, mock.Config{})
+	group := servers.NewGroup(launcher)
+
+	if err := group.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	if err := group.Serve(); err != nil {
+		t.Fatalf("Serve: %v", err)
+	}
+	if calls := launcher.CallsOf(mock.MethodServe); calls != 0 {
+		t.Fatalf("launcher is served %d times after Stop", calls)
+	}
+}
@@ -0,0 +1,93 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"google.golang.org/grpc"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// call describes an in-flight call.
+type call struct {
+	method  string
+	started time.Time
+}
+
+// String returns the method and the duration of the call.
+func (c call) String() string {
+	return fmt.Sprintf("%s (%s)", c.method, time.Since(c.started).Round(time.Millisecond))
+}
+
+// calls tracks in-flight calls of the server.
+type calls struct {
+	mutex  *sync.Mutex
+	nextID uint64
+	active map[uint64]call
+}
+
+// begin registers the start of the call and returns the function, which registers its end.
+func (c *calls) begin(method string) func() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	id := c.nextID
+	c.nextID++
+	c.active[id] = call{method: method, started: time.Now()}
+
+	return func() {
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+
+		delete(c.active, id)
+	}
+}
+
+// snapshot returns in-flight calls ordered by the start time.
+func (c *calls) snapshot() []call {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	snapshot := make([]call, 0, len(c.active))
+	for _, call := range c.active {
+		snapshot = append(snapshot, call)
+	}
+
+	sort.Slice(snapshot, func(i, j int) bool {
+		return snapshot[i].started.Before(snapshot[j].started)
+	})
+	return snapshot
+}
+
+// unaryInterceptor tracks unary calls.
+func (c *calls) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (interface{}, error) {
+	defer c.begin(info.FullMethod)()
+	return handler(ctx, req)
+}
+
+// streamInterceptor tracks stream calls.
+func (c *calls) streamInterceptor(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler) error {
+	defer c.begin(info.FullMethod)()
+	return handler(srv, stream)
+}
+
+// joinCalls returns a comma-separated list of the calls.
+func joinCalls(calls []call) string {
+	descriptions := make([]string, 0, len(calls))
+	for _, call := range calls {
+		descriptions = append(descriptions, call.String())
+	}
+	return strings.Join(descriptions, ", ")
+}
+
+// newCalls - constructor calls.
+func newCalls() *calls {
+	return &calls{
+		mutex:  new(sync.Mutex),
+		active: make(map[uint64]call),
+	}
+}
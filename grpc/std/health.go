@@ -0,0 +1,34 @@
+package server
+
+import (
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// serving reports all the registered services as serving, if the health service is enabled.
+func (s *Server) serving() {
+	if s.health == nil {
+		return
+	}
+
+	s.health.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	for service := range s.grpc.GetServiceInfo() {
+		s.health.SetServingStatus(service, healthpb.HealthCheckResponse_SERVING)
+	}
+}
+
+// draining reports all the registered services as not serving, if the health service is enabled.
+func (s *Server) draining() {
+	if s.health == nil {
+		return
+	}
+
+	s.health.Shutdown()
+}
+
+// newHealth - constructor health.Server, which reports not serving until the server starts serving.
+func newHealth() *health.Server {
+	server := health.NewServer()
+	server.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	return server
+}
@@ -0,0 +1,205 @@
+// Package server provides a gRPC implementation of interfaces servers.
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"github.com/golang-mixins/servers"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"net"
+	"sync"
+	"time"
+)
+
+// Config delivers a set of settings for server implementation.
+// Register is called once by the constructor to register the services.
+// HealthEnabled registers grpc.health.v1.Health, which reports serving only while the server is not stopping.
+// ReflectionEnabled registers the server reflection service.
+// TracerProvider traces Stop, nil stands for the global OpenTelemetry provider.
+type Config struct {
+	Addr              string
+	StopTimeout       time.Duration
+	Logger            servers.Logger
+	TLS               *tls.Config
+	Register          func(registrar grpc.ServiceRegistrar)
+	Options           []grpc.ServerOption
+	HealthEnabled     bool
+	ReflectionEnabled bool
+	TracerProvider    trace.TracerProvider
+}
+
+// Validate validates Config according to predefined rules.
+func (c Config) Validate() error {
+	var errs []error
+
+	if c.Register == nil {
+		errs = append(errs, errors.New("Register can't be nil"))
+	}
+
+	if c.StopTimeout < 0 {
+		errs = append(errs, errors.New("StopTimeout can't be negative"))
+	}
+
+	if err := servers.ValidateAddr(c.Addr); err != nil {
+		errs = append(errs, fmt.Errorf("Addr: %w", err))
+	}
+
+	if c.Logger == nil {
+		errs = append(errs, errors.New("Logger can't be nil"))
+	}
+	return errors.Join(errs...)
+}
+
+// Server predetermines the consistency of the implementation servers.Launcher.
+// Using the methods of the structure, without being initialized by the New() constructor, will lead to panic.
+type Server struct {
+	addr        string
+	stopTimeout time.Duration
+	mutex       *sync.RWMutex
+	shutdown    bool
+	logger      servers.Logger
+	calls       *calls
+	health      *health.Server
+	grpc        *grpc.Server
+	tracer      trace.Tracer
+}
+
+// Serve serving the server.
+// Nil is returned, once the server is stopped by Stop.
+func (s *Server) Serve() error {
+	s.mutex.RLock()
+	shutdown := s.shutdown
+	s.mutex.RUnlock()
+	if shutdown {
+		err := fmt.Errorf("can't serve: %w", servers.ErrServerClosed)
+		s.logger.Error("error Serve", "error", err)
+		return err
+	}
+
+	listener, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		err = &servers.BindError{Addr: s.addr, Err: err}
+		s.logger.Error("error Listen", "error", err)
+		return err
+	}
+	s.logger.Info("listening", "addr", listener.Addr())
+
+	s.serving()
+
+	err = s.grpc.Serve(listener)
+	if errors.Is(err, grpc.ErrServerStopped) {
+		s.logger.Info("exit Serve, server stopped")
+		return nil
+	}
+	if err != nil {
+		err = fmt.Errorf("error serving: %w", err)
+		s.logger.Error("error Serve", "error", err)
+	} else {
+		s.logger.Info("exit Serve")
+	}
+
+	return err
+}
+
+// Stop stops the server.
+// The server is stopped gracefully within StopTimeout or until ctx is done, whichever is earlier,
+// after that it is stopped forcibly and the calls cut by the forced stop are reported by servers.ErrShutdownTimeout.
+func (s *Server) Stop(ctx context.Context) (err error) {
+	ctx, span := s.tracer.Start(ctx, "grpc server stop")
+	defer func() {
+		servers.EndSpan(span, err)
+	}()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.shutdown {
+		return servers.ErrAlreadyStopped
+	}
+
+	s.logger.Info("starting graceful stop grpc server")
+	started := time.Now()
+	s.shutdown = true
+	s.draining()
+
+	var cancel context.CancelFunc
+	if s.stopTimeout != 0 {
+		ctx, cancel = context.WithTimeout(ctx, s.stopTimeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	stopping := make(chan struct{})
+	go func() {
+		s.grpc.GracefulStop()
+		close(stopping)
+	}()
+
+	select {
+	case <-stopping:
+		s.logger.Info("graceful stop successful", "duration", time.Since(started))
+		return nil
+	case <-ctx.Done():
+		s.logger.Info("graceful stop timeout exceeded, starting forced stop")
+
+		cut := s.calls.snapshot()
+		s.grpc.Stop()
+		<-stopping
+
+		err := fmt.Errorf("grpc server stopped forcibly: %w", servers.ErrShutdownTimeout)
+		if len(cut) != 0 {
+			err = fmt.Errorf("grpc server stopped forcibly, %d calls cut: %s: %w",
+				len(cut), joinCalls(cut), servers.ErrShutdownTimeout)
+		}
+		s.logger.Error("forced stop error", "error", err)
+		return err
+	}
+}
+
+// New - constructor Server.
+func New(cfg Config) (*Server, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	server := &Server{
+		addr:        cfg.Addr,
+		stopTimeout: cfg.StopTimeout,
+		mutex:       new(sync.RWMutex),
+		tracer:      servers.Tracer(cfg.TracerProvider),
+		calls:       newCalls(),
+	}
+
+	server.logger = cfg.Logger
+
+	options := make([]grpc.ServerOption, 0, len(cfg.Options)+3)
+	options = append(options,
+		grpc.ChainUnaryInterceptor(server.calls.unaryInterceptor),
+		grpc.ChainStreamInterceptor(server.calls.streamInterceptor),
+	)
+	if cfg.TLS != nil {
+		options = append(options, grpc.Creds(credentials.NewTLS(cfg.TLS)))
+	}
+	options = append(options, cfg.Options...)
+
+	server.grpc = grpc.NewServer(options...)
+	cfg.Register(server.grpc)
+
+	if cfg.HealthEnabled {
+		server.health = newHealth()
+		healthpb.RegisterHealthServer(server.grpc, server.health)
+	}
+	if cfg.ReflectionEnabled {
+		reflection.Register(server.grpc)
+	}
+
+	return server, nil
+}
@@ -0,0 +1,161 @@
+package servers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// HealthChecker delivers an interface to the health check of a dependency (database, cache, downstream service).
+type HealthChecker interface {
+	// Check checks the health of the dependency, ctx bounds the check.
+	Check(ctx context.Context) error
+}
+
+// HealthCheckerFunc adapts the function to HealthChecker.
+type HealthCheckerFunc func(ctx context.Context) error
+
+// Check checks the health of the dependency.
+func (f HealthCheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+// HealthResults maps the names of the checks to their errors, nil error means healthy.
+type HealthResults map[string]error
+
+// Err returns the errors of the failed checks joined, or nil, if all the checks are healthy.
+func (r HealthResults) Err() error {
+	names := make([]string, 0, len(r))
+	for name := range r {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		if r[name] != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, r[name]))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// healthCheck is a registered check together with its timeout and the cached result.
+type healthCheck struct {
+	checker HealthChecker
+	timeout time.Duration
+	ttl     time.Duration
+	mutex   *sync.Mutex
+	checked time.Time
+	err     error
+}
+
+// check returns the cached result, if it isn't older than ttl, otherwise runs the check within timeout.
+func (c *healthCheck) check(ctx context.Context) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if !c.checked.IsZero() && time.Since(c.checked) < c.ttl {
+		return c.err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	c.err = c.checker.Check(ctx)
+	c.checked = time.Now()
+	return c.err
+}
+
+// HealthRegistry predetermines the consistency of the registry, where the dependencies register their health checks
+// to be aggregated (e.g. into the readiness endpoint of a server).
+// Using the methods of the structure, without being initialized by the NewHealthRegistry() constructor,
+// will lead to panic.
+type HealthRegistry struct {
+	mutex  *sync.RWMutex
+	checks map[string]*healthCheck
+}
+
+// Register registers the check under the unique name. Every check is bounded by timeout,
+// its result is cached for ttl (zero disables caching).
+func (r *HealthRegistry) Register(name string, checker HealthChecker, timeout, ttl time.Duration) error {
+	if name == "" {
+		return errors.New("name can't be empty")
+	}
+
+	if checker == nil {
+		return errors.New("checker can't be nil")
+	}
+
+	if timeout <= 0 {
+		return errors.New("timeout must be positive")
+	}
+
+	if ttl < 0 {
+		return errors.New("ttl can't be negative")
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, ok := r.checks[name]; ok {
+		return fmt.Errorf("check %s is already registered", name)
+	}
+
+	r.checks[name] = &healthCheck{
+		checker: checker,
+		timeout: timeout,
+		ttl:     ttl,
+		mutex:   new(sync.Mutex),
+	}
+	return nil
+}
+
+// Unregister removes the check.
+func (r *HealthRegistry) Unregister(name string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	delete(r.checks, name)
+}
+
+// Check runs all the registered checks concurrently.
+func (r *HealthRegistry) Check(ctx context.Context) HealthResults {
+	r.mutex.RLock()
+	checks := make(map[string]*healthCheck, len(r.checks))
+	for name, check := range r.checks {
+		checks[name] = check
+	}
+	r.mutex.RUnlock()
+
+	results := make(HealthResults, len(checks))
+	mutex := new(sync.Mutex)
+
+	wg := new(sync.WaitGroup)
+	wg.Add(len(checks))
+	for name, check := range checks {
+		go func(name string, check *healthCheck) {
+			defer wg.Done()
+
+			err := check.check(ctx)
+
+			mutex.Lock()
+			results[name] = err
+			mutex.Unlock()
+		}(name, check)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// NewHealthRegistry - constructor HealthRegistry.
+func NewHealthRegistry() *HealthRegistry {
+	return &HealthRegistry{
+		mutex:  new(sync.RWMutex),
+		checks: make(map[string]*healthCheck),
+	}
+}
@@ -0,0 +1,122 @@
+// Package debug provides the debug server (pprof, expvar, GC controls) built on the standard server implementation,
+// which is meant to be served on a separate, non-public address alongside the main server (e.g. in servers.Group).
+package debug
+
+import (
+	"errors"
+	"expvar"
+	"fmt"
+	"github.com/golang-mixins/servers"
+	server "github.com/golang-mixins/servers/http/std"
+	"io"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	rdebug "runtime/debug"
+	"strconv"
+	"time"
+)
+
+// defaultWriteTimeout exceeds the default duration of CPU profile (30 seconds).
+const defaultWriteTimeout = time.Minute
+
+// Config delivers a set of settings for the debug server.
+// WriteTimeout must exceed the duration of the requested profiles (30 seconds for CPU profile by default),
+// it defaults to a minute. The rest of the timeouts and Logger left zero default to the ones of server.DefaultConfig.
+// GCEnabled mounts /debug/gc (runs GC), /debug/gc/percent?value=N (sets GC percent) and /debug/gc/free
+// (returns memory to OS), all of them accept POST only.
+type Config struct {
+	Addr         string
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	IdleTimeout  time.Duration
+	StopTimeout  time.Duration
+	Logger       servers.Logger
+	GCEnabled    bool
+}
+
+// Validate validates Config according to predefined rules, the rest are validated by the server.
+func (c Config) Validate() error {
+	var errs []error
+
+	if c.ReadTimeout < 0 || c.WriteTimeout < 0 || c.IdleTimeout < 0 {
+		errs = append(errs, errors.New("ReadTimeout, WriteTimeout and IdleTimeout can't be negative"))
+	}
+	return errors.Join(errs...)
+}
+
+// Router returns the handler serving the debug endpoints.
+func Router(gcEnabled bool) http.Handler {
+	router := http.NewServeMux()
+
+	router.HandleFunc("/debug/pprof/", pprof.Index)
+	router.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	router.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	router.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	router.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	router.Handle("/debug/vars", expvar.Handler())
+
+	if gcEnabled {
+		router.HandleFunc("/debug/gc", post(func(w http.ResponseWriter, r *http.Request) {
+			runtime.GC()
+			io.WriteString(w, "ok")
+		}))
+		router.HandleFunc("/debug/gc/percent", post(func(w http.ResponseWriter, r *http.Request) {
+			percent, err := strconv.Atoi(r.URL.Query().Get("value"))
+			if err != nil {
+				http.Error(w, "value must be an integer", http.StatusBadRequest)
+				return
+			}
+			io.WriteString(w, strconv.Itoa(rdebug.SetGCPercent(percent)))
+		}))
+		router.HandleFunc("/debug/gc/free", post(func(w http.ResponseWriter, r *http.Request) {
+			rdebug.FreeOSMemory()
+			io.WriteString(w, "ok")
+		}))
+	}
+
+	return router
+}
+
+// post restricts the handler to POST requests.
+func post(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		handler(w, r)
+	}
+}
+
+// New - constructor of the debug server.
+func New(cfg Config) (*server.Server, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	config := server.DefaultConfig(cfg.Addr, Router(cfg.GCEnabled))
+	config.WriteTimeout = defaultWriteTimeout
+	if cfg.ReadTimeout != 0 {
+		config.ReadTimeout = cfg.ReadTimeout
+	}
+	if cfg.WriteTimeout != 0 {
+		config.WriteTimeout = cfg.WriteTimeout
+	}
+	if cfg.IdleTimeout != 0 {
+		config.IdleTimeout = cfg.IdleTimeout
+	}
+	if cfg.StopTimeout != 0 {
+		config.StopTimeout = cfg.StopTimeout
+	}
+	if cfg.Logger != nil {
+		config.Logger = cfg.Logger
+	}
+
+	srv, err := server.New(config)
+	if err != nil {
+		return nil, fmt.Errorf("can't create debug server: %w", err)
+	}
+	return srv, nil
+}
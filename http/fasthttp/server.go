@@ -0,0 +1,196 @@
+// Package server provides a fasthttp implementation of interfaces servers.
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golang-mixins/servers"
+	"github.com/valyala/fasthttp"
+	"go.opentelemetry.io/otel/trace"
+	"net"
+	"sync"
+	"time"
+)
+
+// Config delivers a set of settings for server implementation.
+// MaxHeaderBytes limits the read buffer per connection, which bounds the request header size in fasthttp.
+// ReadHeaderTimeout bounds the reading of the request headers. fasthttp has no header timeout of its own,
+// so its ReadTimeout is set to ReadHeaderTimeout and HeaderReceived extends the deadline by ReadTimeout for the body
+// (unless ReadTimeout is zero, the body is read within ReadHeaderTimeout then), IdleTimeout defaults to ReadTimeout.
+// TracerProvider traces Stop, nil stands for the global OpenTelemetry provider.
+type Config struct {
+	Addr               string
+	ReadTimeout        time.Duration
+	ReadHeaderTimeout  time.Duration
+	WriteTimeout       time.Duration
+	IdleTimeout        time.Duration
+	StopTimeout        time.Duration
+	MaxHeaderBytes     int
+	MaxRequestBodySize int
+	Logger             servers.Logger
+	Router             fasthttp.RequestHandler
+	KeepAliveEnabled   bool
+	TracerProvider     trace.TracerProvider
+}
+
+// Validate validates Config according to predefined rules.
+func (c Config) Validate() error {
+	var errs []error
+
+	if c.Router == nil {
+		errs = append(errs, errors.New("Router can't be nil"))
+	}
+
+	if c.StopTimeout < 0 {
+		errs = append(errs, errors.New("StopTimeout can't be negative"))
+	}
+
+	if c.ReadHeaderTimeout < 0 {
+		errs = append(errs, errors.New("ReadHeaderTimeout can't be negative"))
+	}
+
+	if err := servers.ValidateAddr(c.Addr); err != nil {
+		errs = append(errs, fmt.Errorf("Addr: %w", err))
+	}
+
+	if c.Logger == nil {
+		errs = append(errs, errors.New("Logger can't be nil"))
+	}
+	return errors.Join(errs...)
+}
+
+// Server predetermines the consistency of the implementation servers.Launcher.
+// Using the methods of the structure, without being initialized by the New() constructor, will lead to panic.
+type Server struct {
+	addr        string
+	stopTimeout time.Duration
+	mutex       *sync.RWMutex
+	shutdown    bool
+	logger      servers.Logger
+	fasthttp    *fasthttp.Server
+	tracer      trace.Tracer
+}
+
+// Serve serving the server.
+// Nil is returned, once the server is shut down by Stop.
+func (s *Server) Serve() error {
+	s.mutex.RLock()
+	shutdown := s.shutdown
+	s.mutex.RUnlock()
+	if shutdown {
+		err := fmt.Errorf("can't serve: %w", servers.ErrServerClosed)
+		s.logger.Error("error Serve", "error", err)
+		return err
+	}
+
+	// tcp rather than tcp4 of fasthttp ListenAndServe, so that the IPv6 addresses accepted by Validate are bound.
+	listener, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		err = &servers.BindError{Addr: s.addr, Err: err}
+		s.logger.Error("error Listen", "error", err)
+		return err
+	}
+	s.logger.Info("listening", "addr", listener.Addr())
+
+	err = s.fasthttp.Serve(listener)
+	if err != nil {
+		err = fmt.Errorf("error serving: %w", err)
+		s.logger.Error("error Serve", "error", err)
+	} else {
+		s.logger.Info("exit Serve")
+	}
+
+	return err
+}
+
+// Stop stops the server.
+// The server is shut down within StopTimeout or until ctx is done, whichever is earlier.
+// fasthttp has no forced close, so the connections still open after that are reported by servers.ErrShutdownTimeout.
+func (s *Server) Stop(ctx context.Context) (err error) {
+	ctx, span := s.tracer.Start(ctx, "fasthttp server stop")
+	defer func() {
+		servers.EndSpan(span, err)
+	}()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.shutdown {
+		return servers.ErrAlreadyStopped
+	}
+
+	s.logger.Info("starting shutdown fasthttp server")
+	started := time.Now()
+	s.shutdown = true
+
+	var cancel context.CancelFunc
+	if s.stopTimeout != 0 {
+		ctx, cancel = context.WithTimeout(ctx, s.stopTimeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	err = s.fasthttp.ShutdownWithContext(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			err = servers.ErrShutdownTimeout
+		}
+		err = fmt.Errorf("can't shutdown fasthttp server: %w", err)
+		s.logger.Error("shutdown error", "error", err)
+		return err
+	}
+
+	s.logger.Info("shutdown successful", "duration", time.Since(started))
+	return nil
+}
+
+// New - constructor Server.
+func New(cfg Config) (*Server, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	server := &Server{
+		addr:        cfg.Addr,
+		stopTimeout: cfg.StopTimeout,
+		mutex:       new(sync.RWMutex),
+		tracer:      servers.Tracer(cfg.TracerProvider),
+	}
+
+	server.logger = cfg.Logger
+
+	server.fasthttp = &fasthttp.Server{
+		Handler:          cfg.Router,
+		Logger:           server.logger,
+		DisableKeepalive: !cfg.KeepAliveEnabled,
+	}
+
+	if cfg.ReadTimeout != 0 {
+		server.fasthttp.ReadTimeout = cfg.ReadTimeout
+	}
+	if cfg.WriteTimeout != 0 {
+		server.fasthttp.WriteTimeout = cfg.WriteTimeout
+	}
+	if cfg.IdleTimeout != 0 {
+		server.fasthttp.IdleTimeout = cfg.IdleTimeout
+	}
+	if cfg.ReadHeaderTimeout != 0 {
+		if cfg.IdleTimeout == 0 {
+			server.fasthttp.IdleTimeout = cfg.ReadTimeout
+		}
+		server.fasthttp.ReadTimeout = cfg.ReadHeaderTimeout
+		server.fasthttp.HeaderReceived = func(*fasthttp.RequestHeader) fasthttp.RequestConfig {
+			return fasthttp.RequestConfig{ReadTimeout: cfg.ReadTimeout}
+		}
+	}
+	if cfg.MaxHeaderBytes != 0 {
+		server.fasthttp.ReadBufferSize = cfg.MaxHeaderBytes
+	}
+	if cfg.MaxRequestBodySize != 0 {
+		server.fasthttp.MaxRequestBodySize = cfg.MaxRequestBodySize
+	}
+
+	return server, nil
+}
@@ -0,0 +1,214 @@
+// Package server provides an HTTP/3 (QUIC) implementation of interfaces servers.
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"github.com/golang-mixins/servers"
+	"github.com/quic-go/quic-go"
+	"github.com/quic-go/quic-go/http3"
+	"go.opentelemetry.io/otel/trace"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Config delivers a set of settings for server implementation.
+// TLS is mandatory for QUIC, so it must provide Certificates or GetCertificate.
+// TracerProvider traces Stop, nil stands for the global OpenTelemetry provider.
+type Config struct {
+	Addr                 string
+	HandshakeIdleTimeout time.Duration
+	IdleTimeout          time.Duration
+	StopTimeout          time.Duration
+	MaxHeaderBytes       int
+	MaxIncomingStreams   int64
+	Logger               servers.Logger
+	Router               http.Handler
+	TLS                  *tls.Config
+	TracerProvider       trace.TracerProvider
+}
+
+// Validate validates Config according to predefined rules.
+func (c Config) Validate() error {
+	var errs []error
+
+	if c.Router == nil {
+		errs = append(errs, errors.New("Router can't be nil"))
+	}
+
+	if c.StopTimeout < 0 {
+		errs = append(errs, errors.New("StopTimeout can't be negative"))
+	}
+
+	if err := servers.ValidateAddr(c.Addr); err != nil {
+		errs = append(errs, fmt.Errorf("Addr: %w", err))
+	}
+
+	if c.Logger == nil {
+		errs = append(errs, errors.New("Logger can't be nil"))
+	}
+
+	if c.TLS == nil {
+		errs = append(errs, errors.New("TLS can't be nil"))
+	} else if len(c.TLS.Certificates) == 0 && c.TLS.GetCertificate == nil && c.TLS.GetConfigForClient == nil {
+		errs = append(errs, errors.New("TLS must provide certificates"))
+	}
+	return errors.Join(errs...)
+}
+
+// Server predetermines the consistency of the implementation servers.Launcher.
+// Using the methods of the structure, without being initialized by the New() constructor, will lead to panic.
+type Server struct {
+	stopTimeout time.Duration
+	mutex       *sync.RWMutex
+	shutdown    bool
+	logger      servers.Logger
+	http3       *http3.Server
+	tracer      trace.Tracer
+}
+
+// Serve serving the server.
+// Nil is returned, once the server is closed by Stop.
+func (s *Server) Serve() error {
+	s.mutex.RLock()
+	shutdown := s.shutdown
+	s.mutex.RUnlock()
+	if shutdown {
+		err := fmt.Errorf("can't serve: %w", servers.ErrServerClosed)
+		s.logger.Error("error Serve", "error", err)
+		return err
+	}
+
+	conn, err := net.ListenPacket("udp", s.http3.Addr)
+	if err != nil {
+		err = &servers.BindError{Addr: s.http3.Addr, Err: err}
+		s.logger.Error("error Listen", "error", err)
+		return err
+	}
+	defer conn.Close()
+	s.logger.Info("listening", "addr", conn.LocalAddr())
+
+	err = s.http3.Serve(conn)
+	if errors.Is(err, http.ErrServerClosed) {
+		s.logger.Info("exit Serve, server closed")
+		return nil
+	}
+	if err != nil {
+		err = fmt.Errorf("error serving: %w", err)
+		s.logger.Error("error Serve", "error", err)
+	} else {
+		s.logger.Error("unexpected exit Serve")
+	}
+
+	return err
+}
+
+// Stop stops the server.
+// Shutdown sends GOAWAY to the clients and drains the streams within StopTimeout or until ctx is done,
+// whichever is earlier, the rest are closed and servers.ErrShutdownTimeout is reported.
+// The close has the rest of StopTimeout and ctx, but at least servers.ForceCloseTimeout, like in http/std.
+func (s *Server) Stop(ctx context.Context) (err error) {
+	ctx, span := s.tracer.Start(ctx, "http3 server stop")
+	defer func() {
+		servers.EndSpan(span, err)
+	}()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.shutdown {
+		return servers.ErrAlreadyStopped
+	}
+
+	s.logger.Info("starting shutdown http3 server")
+	started := time.Now()
+	s.shutdown = true
+
+	var cancel context.CancelFunc
+	if s.stopTimeout != 0 {
+		ctx, cancel = context.WithTimeout(ctx, s.stopTimeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	err = s.http3.Shutdown(ctx)
+	if err == nil {
+		s.logger.Info("shutdown successful", "duration", time.Since(started))
+		return nil
+	}
+	s.logger.Error("shutdown error", "error", err)
+
+	closing := make(chan error, 1)
+
+	timer := time.NewTimer(servers.ForceCloseBudget(ctx))
+	defer timer.Stop()
+
+	go func() {
+		err := s.http3.Close()
+		if err != nil {
+			err = fmt.Errorf("error closing: %w", err)
+		}
+		closing <- err
+		close(closing)
+	}()
+
+	select {
+	case err := <-closing:
+		if err != nil {
+			err = fmt.Errorf("can't close http3 server: %w", err)
+			s.logger.Error("closing error", "error", err)
+		} else {
+			s.logger.Info("closing successful", "duration", time.Since(started))
+			err = fmt.Errorf("http3 server closed forcibly: %w", servers.ErrShutdownTimeout)
+		}
+		return err
+	case <-timer.C:
+		err := fmt.Errorf("can't close http3 server: %w", servers.ErrShutdownTimeout)
+		s.logger.Error("closing timeout exceeded error", "error", err)
+		<-closing
+		return err
+	}
+}
+
+// New - constructor Server.
+func New(cfg Config) (*Server, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	server := &Server{
+		stopTimeout: cfg.StopTimeout,
+		mutex:       new(sync.RWMutex),
+		tracer:      servers.Tracer(cfg.TracerProvider),
+	}
+
+	server.logger = cfg.Logger
+
+	server.http3 = &http3.Server{
+		Addr:       cfg.Addr,
+		Handler:    cfg.Router,
+		TLSConfig:  http3.ConfigureTLSConfig(cfg.TLS.Clone()),
+		QUICConfig: new(quic.Config),
+	}
+
+	if cfg.HandshakeIdleTimeout != 0 {
+		server.http3.QUICConfig.HandshakeIdleTimeout = cfg.HandshakeIdleTimeout
+	}
+	if cfg.IdleTimeout != 0 {
+		server.http3.IdleTimeout = cfg.IdleTimeout
+		server.http3.QUICConfig.MaxIdleTimeout = cfg.IdleTimeout
+	}
+	if cfg.MaxIncomingStreams != 0 {
+		server.http3.QUICConfig.MaxIncomingStreams = cfg.MaxIncomingStreams
+	}
+	if cfg.MaxHeaderBytes != 0 {
+		server.http3.MaxHeaderBytes = cfg.MaxHeaderBytes
+	}
+
+	return server, nil
+}
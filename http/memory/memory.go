@@ -0,0 +1,147 @@
+// Package memory provides the standard server implementation serving the in-memory listener, which is dialed
+// without the sockets of the OS, for the hermetic tests of the full HTTP stacks (including Stop).
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	server "github.com/golang-mixins/servers/http/std"
+	"net"
+	"net/http"
+	"sync"
+)
+
+// network is the network of the in-memory addresses.
+const network = "memory"
+
+// addr is the address of the in-memory listener and connections.
+type addr struct{}
+
+// Network returns the name of the network.
+func (addr) Network() string {
+	return network
+}
+
+// String returns the address.
+func (addr) String() string {
+	return network
+}
+
+// Listener predetermines the consistency of the implementation net.Listener, which accepts the in-memory connections
+// dialed by DialContext.
+// Using the methods of the structure, without being initialized by the NewListener() constructor, will lead to panic.
+type Listener struct {
+	conns     chan net.Conn
+	closed    chan struct{}
+	closeOnce *sync.Once
+}
+
+// Accept waits for and returns the next connection.
+func (l *Listener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.closed:
+		return nil, net.ErrClosed
+	}
+}
+
+// Close closes the listener, the connections accepted already are kept open.
+func (l *Listener) Close() error {
+	err := net.ErrClosed
+	l.closeOnce.Do(func() {
+		close(l.closed)
+		err = nil
+	})
+	return err
+}
+
+// Addr returns the in-memory address of the listener.
+func (l *Listener) Addr() net.Addr {
+	return addr{}
+}
+
+// DialContext dials the listener, the network and the address are ignored, so that it fits http.Transport.DialContext.
+// The dial waits until the connection is accepted, ctx is done or the listener is closed.
+func (l *Listener) DialContext(ctx context.Context, _, _ string) (net.Conn, error) {
+	client, server := net.Pipe()
+	select {
+	case l.conns <- server:
+		return client, nil
+	case <-l.closed:
+	case <-ctx.Done():
+		client.Close()
+		server.Close()
+		return nil, fmt.Errorf("can't dial %s: %w", network, ctx.Err())
+	}
+
+	client.Close()
+	server.Close()
+	return nil, fmt.Errorf("can't dial %s: connection refused: %w", network, net.ErrClosed)
+}
+
+// NewListener - constructor Listener.
+func NewListener() *Listener {
+	return &Listener{
+		conns:     make(chan net.Conn),
+		closed:    make(chan struct{}),
+		closeOnce: new(sync.Once),
+	}
+}
+
+// Server predetermines the consistency of the implementation servers.Launcher, which is the standard server
+// serving the in-memory listener.
+// Using the methods of the structure, without being initialized by the New() constructor, will lead to panic.
+type Server struct {
+	*server.Server
+	listener *Listener
+	scheme   string
+}
+
+// DialContext dials the server in memory.
+func (s *Server) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	return s.listener.DialContext(ctx, network, address)
+}
+
+// URL returns the base URL of the server, the host of which is ignored by the dialer.
+func (s *Server) URL() string {
+	return s.scheme + "://" + network
+}
+
+// Client returns the client dialing the server in memory, whatever the host of the URL is.
+// TLS client settings (e.g. RootCAs) are left to the caller, if TLS is configured.
+func (s *Server) Client() *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			DialContext:       s.DialContext,
+			ForceAttemptHTTP2: true,
+		},
+	}
+}
+
+// New - constructor Server serving the in-memory listener instead of Addr, which has to be empty.
+func New(cfg server.Config) (*Server, error) {
+	if cfg.Addr != "" || cfg.Listener != nil {
+		return nil, errors.New("Addr and Listener can't be set, the in-memory listener is served")
+	}
+
+	listener := NewListener()
+	cfg.Listener = listener
+
+	launcher, err := server.New(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	scheme := "http"
+	if cfg.TLS != nil {
+		scheme = "https"
+	}
+
+	return &Server{
+		Server:   launcher,
+		listener: listener,
+		scheme:   scheme,
+	}, nil
+}
@@ -0,0 +1,79 @@
+package metrics
+
+import (
+	server "github.com/golang-mixins/servers/http/std"
+	"github.com/prometheus/client_golang/prometheus"
+	"net/http"
+)
+
+// collector predetermines the consistency of the implementation prometheus.Collector,
+// which exports the stats of the server.
+type collector struct {
+	source              server.StatsSource
+	openConnections     *prometheus.Desc
+	connections         *prometheus.Desc
+	hijackedConnections *prometheus.Desc
+	openHijacked        *prometheus.Desc
+	activeRequests      *prometheus.Desc
+	requests            *prometheus.Desc
+	shedRequests        *prometheus.Desc
+}
+
+// Describe sends the descriptors of the metrics.
+func (c collector) Describe(descs chan<- *prometheus.Desc) {
+	descs <- c.openConnections
+	descs <- c.connections
+	descs <- c.hijackedConnections
+	descs <- c.openHijacked
+	descs <- c.activeRequests
+	descs <- c.requests
+	descs <- c.shedRequests
+}
+
+// Collect sends the metrics taken from the stats snapshot.
+func (c collector) Collect(metrics chan<- prometheus.Metric) {
+	stats := c.source.Stats()
+
+	metrics <- prometheus.MustNewConstMetric(c.openConnections, prometheus.GaugeValue,
+		float64(stats.OpenConnections))
+	for _, state := range []http.ConnState{http.StateNew, http.StateActive, http.StateIdle} {
+		metrics <- prometheus.MustNewConstMetric(c.connections, prometheus.GaugeValue,
+			float64(stats.Connections[state]), state.String())
+	}
+	metrics <- prometheus.MustNewConstMetric(c.hijackedConnections, prometheus.CounterValue,
+		float64(stats.HijackedConnections))
+	metrics <- prometheus.MustNewConstMetric(c.openHijacked, prometheus.GaugeValue,
+		float64(stats.OpenHijackedConnections))
+	metrics <- prometheus.MustNewConstMetric(c.activeRequests, prometheus.GaugeValue,
+		float64(stats.ActiveRequests))
+	metrics <- prometheus.MustNewConstMetric(c.requests, prometheus.CounterValue,
+		float64(stats.Requests))
+	metrics <- prometheus.MustNewConstMetric(c.shedRequests, prometheus.CounterValue,
+		float64(stats.ShedRequests))
+}
+
+// NewCollector - constructor of prometheus.Collector, which exports the stats of the server
+// under the namespace with the constant labels.
+func NewCollector(source server.StatsSource, namespace string, labels prometheus.Labels) prometheus.Collector {
+	name := func(name string) string {
+		return prometheus.BuildFQName(namespace, "http_server", name)
+	}
+
+	return collector{
+		source: source,
+		openConnections: prometheus.NewDesc(name("open_connections"),
+			"Number of open connections.", nil, labels),
+		connections: prometheus.NewDesc(name("connections"),
+			"Number of open connections by state.", []string{"state"}, labels),
+		hijackedConnections: prometheus.NewDesc(name("hijacked_connections_total"),
+			"Total number of hijacked connections.", nil, labels),
+		openHijacked: prometheus.NewDesc(name("open_hijacked_connections"),
+			"Number of hijacked connections not closed yet, if they are tracked.", nil, labels),
+		activeRequests: prometheus.NewDesc(name("active_requests"),
+			"Number of requests in flight.", nil, labels),
+		requests: prometheus.NewDesc(name("requests_total"),
+			"Total number of requests.", nil, labels),
+		shedRequests: prometheus.NewDesc(name("shed_requests_total"),
+			"Total number of requests rejected by load shedding.", nil, labels),
+	}
+}
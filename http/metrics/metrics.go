@@ -0,0 +1,85 @@
+// Package metrics provides the Prometheus metrics server built on the standard server implementation,
+// which is meant to be served on a dedicated address alongside the main server (e.g. in servers.Group).
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"github.com/golang-mixins/servers"
+	server "github.com/golang-mixins/servers/http/std"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Config delivers a set of settings for the metrics server.
+// Path defaults to /metrics, Gatherer defaults to prometheus.DefaultGatherer.
+// The timeouts and Logger left zero default to the ones of server.DefaultConfig.
+type Config struct {
+	Addr         string
+	Path         string
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	IdleTimeout  time.Duration
+	StopTimeout  time.Duration
+	Logger       servers.Logger
+	Gatherer     prometheus.Gatherer
+}
+
+// Validate validates Config according to predefined rules, the rest are validated by the server.
+func (c Config) Validate() error {
+	var errs []error
+
+	if c.Path != "" && !strings.HasPrefix(c.Path, "/") {
+		errs = append(errs, errors.New("Path must start with /"))
+	}
+	return errors.Join(errs...)
+}
+
+// New - constructor of the metrics server.
+func New(cfg Config) (*server.Server, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	path := cfg.Path
+	if path == "" {
+		path = "/metrics"
+	}
+
+	gatherer := cfg.Gatherer
+	if gatherer == nil {
+		gatherer = prometheus.DefaultGatherer
+	}
+
+	router := http.NewServeMux()
+	config := server.DefaultConfig(cfg.Addr, router)
+	if cfg.ReadTimeout != 0 {
+		config.ReadTimeout = cfg.ReadTimeout
+	}
+	if cfg.WriteTimeout != 0 {
+		config.WriteTimeout = cfg.WriteTimeout
+	}
+	if cfg.IdleTimeout != 0 {
+		config.IdleTimeout = cfg.IdleTimeout
+	}
+	if cfg.StopTimeout != 0 {
+		config.StopTimeout = cfg.StopTimeout
+	}
+	if cfg.Logger != nil {
+		config.Logger = cfg.Logger
+	}
+
+	router.Handle(path, promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{
+		ErrorLog:      servers.StdLog(config.Logger),
+		ErrorHandling: promhttp.ContinueOnError,
+	}))
+
+	srv, err := server.New(config)
+	if err != nil {
+		return nil, fmt.Errorf("can't create metrics server: %w", err)
+	}
+	return srv, nil
+}
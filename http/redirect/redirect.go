@@ -0,0 +1,104 @@
+// Package redirect provides the launcher redirecting plain HTTP to HTTPS built on the standard server implementation,
+// which is meant to be served alongside the HTTPS server (e.g. in servers.Group).
+package redirect
+
+import (
+	"errors"
+	"fmt"
+	"github.com/golang-mixins/servers"
+	server "github.com/golang-mixins/servers/http/std"
+	"golang.org/x/crypto/acme/autocert"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config delivers a set of settings for the redirect server.
+// Addr defaults to ":80". The requests are redirected permanently (308) to Host, or to the host of the request,
+// if Host is empty, with HTTPSPort, unless it is 0 or 443. The requests without the host are refused with 400.
+// Autocert, if set, answers ACME HTTP-01 challenges instead of redirecting them.
+// Logger defaults to the logger of server.DefaultConfig.
+type Config struct {
+	Addr        string
+	Host        string
+	HTTPSPort   int
+	Autocert    *autocert.Manager
+	StopTimeout time.Duration
+	Logger      servers.Logger
+}
+
+// Validate validates Config according to predefined rules, the rest are validated by the server.
+func (c Config) Validate() error {
+	var errs []error
+
+	if c.HTTPSPort < 0 || c.HTTPSPort > 65535 {
+		errs = append(errs, errors.New("HTTPSPort must be between 0 and 65535"))
+	}
+
+	if c.StopTimeout < 0 {
+		errs = append(errs, errors.New("StopTimeout can't be negative"))
+	}
+	return errors.Join(errs...)
+}
+
+// Handler returns the handler redirecting the requests to HTTPS according to Config.
+func Handler(cfg Config) http.Handler {
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := cfg.Host
+		if host == "" {
+			host = r.Host
+			if hostname, _, err := net.SplitHostPort(r.Host); err == nil {
+				host = hostname
+			}
+			// IPv6 address without the port, e.g. "[::1]", keeps its brackets.
+			host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+		}
+
+		if host == "" {
+			http.Error(w, "host is missing", http.StatusBadRequest)
+			return
+		}
+
+		switch {
+		case cfg.HTTPSPort != 0 && cfg.HTTPSPort != 443:
+			host = net.JoinHostPort(host, strconv.Itoa(cfg.HTTPSPort))
+		case strings.Contains(host, ":"):
+			host = "[" + host + "]"
+		}
+
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusPermanentRedirect)
+	})
+
+	if cfg.Autocert != nil {
+		handler = cfg.Autocert.HTTPHandler(handler)
+	}
+	return handler
+}
+
+// New - constructor of the redirect server.
+func New(cfg Config) (*server.Server, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	addr := cfg.Addr
+	if addr == "" {
+		addr = ":80"
+	}
+
+	config := server.DefaultConfig(addr, Handler(cfg))
+	if cfg.StopTimeout != 0 {
+		config.StopTimeout = cfg.StopTimeout
+	}
+	if cfg.Logger != nil {
+		config.Logger = cfg.Logger
+	}
+
+	srv, err := server.New(config)
+	if err != nil {
+		return nil, fmt.Errorf("can't create redirect server: %w", err)
+	}
+	return srv, nil
+}
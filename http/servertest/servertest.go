@@ -0,0 +1,134 @@
+// Package servertest provides the launcher on top of httptest.Server for the integration tests: it serves
+// on a random port of the loopback interface, like httptest.Server, but it is served and stopped gracefully
+// like servers implementations, e.g. in servers.Group.
+package servertest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golang-mixins/servers"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+)
+
+// Config delivers a set of settings for Server implementation.
+// TLS serves HTTPS with the certificate of httptest, which is trusted by Client.
+// HTTP2 enables HTTP/2, see httptest.Server.EnableHTTP2.
+type Config struct {
+	Handler http.Handler
+	TLS     bool
+	HTTP2   bool
+}
+
+// Validate validates Config according to predefined rules.
+func (c Config) Validate() error {
+	var errs []error
+
+	if c.Handler == nil {
+		errs = append(errs, errors.New("Handler can't be nil"))
+	}
+	return errors.Join(errs...)
+}
+
+// Server predetermines the consistency of the implementation servers.Launcher on top of httptest.Server.
+// Using the methods of the structure, without being initialized by the New() constructor, will lead to panic.
+type Server struct {
+	http     *httptest.Server
+	tls      bool
+	mutex    *sync.Mutex
+	started  bool
+	stopped  bool
+	stopping chan struct{}
+	ready    chan struct{}
+}
+
+// Serve serving the server.
+// Nil is returned, once the server is stopped by Stop.
+func (s *Server) Serve() error {
+	s.mutex.Lock()
+	if s.stopped {
+		s.mutex.Unlock()
+		return fmt.Errorf("can't serve: %w", servers.ErrServerClosed)
+	}
+	if s.started {
+		s.mutex.Unlock()
+		return errors.New("can't serve: server is served already")
+	}
+	s.started = true
+	if s.tls {
+		s.http.StartTLS()
+	} else {
+		s.http.Start()
+	}
+	close(s.ready)
+	s.mutex.Unlock()
+
+	<-s.stopping
+	return nil
+}
+
+// Stop stops the server.
+// The server is shut down gracefully until ctx is done, after that the connections are closed
+// and servers.ErrShutdownTimeout is reported.
+func (s *Server) Stop(ctx context.Context) (err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.stopped {
+		return servers.ErrAlreadyStopped
+	}
+	s.stopped = true
+	close(s.stopping)
+
+	if s.started {
+		if err = s.http.Config.Shutdown(ctx); err != nil {
+			s.http.CloseClientConnections()
+			err = fmt.Errorf("http test server closed forcibly: %w", servers.ErrShutdownTimeout)
+		}
+	}
+	s.http.Close()
+
+	return err
+}
+
+// Ready returns the channel, which is closed once the server is listening and accepting connections.
+func (s *Server) Ready() <-chan struct{} {
+	return s.ready
+}
+
+// URL returns the base URL of the server, e.g. "http://127.0.0.1:34567", once it is ready.
+func (s *Server) URL() string {
+	<-s.ready
+	return s.http.URL
+}
+
+// Client returns the client of the server, which trusts its certificate, if TLS is served, once it is ready.
+func (s *Server) Client() *http.Client {
+	<-s.ready
+	return s.http.Client()
+}
+
+// HTTPTest returns the underlying httptest.Server, e.g. to access its Config or Certificate.
+func (s *Server) HTTPTest() *httptest.Server {
+	return s.http
+}
+
+// New - constructor Server, the listener is bound by the constructor, like by httptest.NewUnstartedServer.
+func New(cfg Config) (*Server, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	test := httptest.NewUnstartedServer(cfg.Handler)
+	test.EnableHTTP2 = cfg.HTTP2
+
+	return &Server{
+		http:     test,
+		tls:      cfg.TLS,
+		mutex:    new(sync.Mutex),
+		stopping: make(chan struct{}),
+		ready:    make(chan struct{}),
+	}, nil
+}
@@ -0,0 +1,41 @@
+package server
+
+import (
+	"github.com/golang-mixins/servers"
+	"net/http"
+	"time"
+)
+
+// accessLog wraps the handler logging each request by the logger, once it is served.
+func accessLog(logger servers.Logger, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started := time.Now()
+		rec := &recorder{ResponseWriter: w}
+
+		handler.ServeHTTP(rec, r)
+
+		status := rec.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		logger.Info("request",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", status,
+			"bytes", rec.written,
+			"latency", time.Since(started),
+			"remote_addr", r.RemoteAddr,
+			"client_ip", clientIP(r),
+			"request_id", RequestIDFromContext(r.Context()),
+		)
+	})
+}
+
+// clientIP returns the client IP resolved by the server, or empty string, if there is none.
+func clientIP(r *http.Request) string {
+	addr, ok := ClientIPFromContext(r.Context())
+	if !ok {
+		return ""
+	}
+	return addr.String()
+}
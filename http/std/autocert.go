@@ -0,0 +1,62 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"github.com/golang-mixins/servers"
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// AutocertConfig delivers a set of settings for obtaining certificates automatically via ACME (Let's Encrypt).
+// The certificates are cached either in CacheDir, or in Cache, e.g. the shared one of certs/cache/...,
+// so that the replicas don't issue their own certificates hitting the rate limits of the CA.
+// ChallengeAddr, when set, enables a listener answering HTTP-01 challenges (and redirecting other requests to https).
+type AutocertConfig struct {
+	HostWhitelist []string
+	CacheDir      string
+	Cache         autocert.Cache
+	Email         string
+	DirectoryURL  string
+	ChallengeAddr string
+}
+
+// Validate validates AutocertConfig according to predefined rules.
+func (c AutocertConfig) Validate() error {
+	var errs []error
+
+	if len(c.HostWhitelist) == 0 {
+		errs = append(errs, errors.New("HostWhitelist can't be empty"))
+	}
+
+	if (c.CacheDir == "") == (c.Cache == nil) {
+		errs = append(errs, errors.New("exactly one of CacheDir and Cache must be set"))
+	}
+
+	if c.ChallengeAddr != "" {
+		if err := servers.ValidateAddr(c.ChallengeAddr); err != nil {
+			errs = append(errs, fmt.Errorf("ChallengeAddr: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// manager assembles autocert.Manager according to AutocertConfig.
+func (c AutocertConfig) manager() *autocert.Manager {
+	cache := c.Cache
+	if cache == nil {
+		cache = autocert.DirCache(c.CacheDir)
+	}
+
+	manager := &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		Cache:      cache,
+		HostPolicy: autocert.HostWhitelist(c.HostWhitelist...),
+		Email:      c.Email,
+	}
+
+	if c.DirectoryURL != "" {
+		manager.Client = &acme.Client{DirectoryURL: c.DirectoryURL}
+	}
+	return manager
+}
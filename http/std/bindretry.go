@@ -0,0 +1,56 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"github.com/golang-mixins/servers"
+	"net"
+	"syscall"
+	"time"
+)
+
+// BindRetryConfig delivers a set of settings for the retries of the bind failed with EADDRINUSE,
+// e.g. while the previous process is still releasing the address on a fast restart.
+// Attempts bounds the binds in total, the first retry waits Backoff, which doubles on each next one.
+type BindRetryConfig struct {
+	Attempts int           `config:"attempts"`
+	Backoff  time.Duration `config:"backoff"`
+}
+
+// Validate validates BindRetryConfig according to predefined rules.
+func (c BindRetryConfig) Validate() error {
+	var errs []error
+
+	if c.Attempts < 2 {
+		errs = append(errs, errors.New("Attempts must be at least 2"))
+	}
+
+	if c.Backoff <= 0 {
+		errs = append(errs, errors.New("Backoff must be positive"))
+	}
+	return errors.Join(errs...)
+}
+
+// listenRetrying binds the listeners, retrying EADDRINUSE according to BindRetry, if it is set, until Stop is called.
+func (s *Server) listenRetrying() ([]net.Listener, error) {
+	listeners, err := s.listen()
+	if s.bindRetry == nil {
+		return listeners, err
+	}
+
+	backoff := s.bindRetry.Backoff
+	for attempt := 2; attempt <= s.bindRetry.Attempts && errors.Is(err, syscall.EADDRINUSE); attempt++ {
+		s.log().Info("address in use, retrying bind", "attempt", attempt, "backoff", backoff)
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-s.stopping:
+			timer.Stop()
+			return nil, fmt.Errorf("bind retries interrupted: %w", servers.ErrServerClosed)
+		}
+		backoff *= 2
+
+		listeners, err = s.listen()
+	}
+	return listeners, err
+}
@@ -0,0 +1,130 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"github.com/golang-mixins/servers/certs"
+)
+
+// clientVerifier verifies the client certificates against the current pool of ClientCAFile and checks
+// the revocation of the verified chains.
+// crypto/tls verifies against the pool fixed in tls.Config, so the reloaded pool is verified here instead.
+// The checks run in VerifyConnection, which is called on the resumed sessions too, unlike VerifyPeerCertificate,
+// so the certificate revoked or issued by the CA removed since isn't accepted by the session ticket.
+type clientVerifier struct {
+	clientCAs  *certs.CAFile
+	revocation certs.RevocationChecker
+	verify     func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error
+	connection func(state tls.ConnectionState) error
+}
+
+// verifyConnection implements tls.Config.VerifyConnection.
+func (v clientVerifier) verifyConnection(state tls.ConnectionState) error {
+	if v.clientCAs != nil && len(state.PeerCertificates) != 0 {
+		chains, err := v.verifyChains(state.PeerCertificates)
+		if err != nil {
+			return err
+		}
+		state.VerifiedChains = chains
+	}
+
+	if v.revocation != nil && len(state.VerifiedChains) != 0 {
+		var errs []error
+		for _, chain := range state.VerifiedChains {
+			err := certs.CheckChain(v.revocation, chain)
+			if err == nil {
+				errs = nil
+				break
+			}
+			errs = append(errs, err)
+		}
+
+		if err := errors.Join(errs...); err != nil {
+			return fmt.Errorf("client certificate rejected: %w", err)
+		}
+	}
+
+	if v.verify != nil {
+		rawCerts := make([][]byte, 0, len(state.PeerCertificates))
+		for _, certificate := range state.PeerCertificates {
+			rawCerts = append(rawCerts, certificate.Raw)
+		}
+		if err := v.verify(rawCerts, state.VerifiedChains); err != nil {
+			return err
+		}
+	}
+
+	if v.connection != nil {
+		return v.connection(state)
+	}
+	return nil
+}
+
+// verifyChains verifies the client certificate against the current pool, the rest of certificates are intermediates.
+func (v clientVerifier) verifyChains(certificates []*x509.Certificate) ([][]*x509.Certificate, error) {
+	intermediates := x509.NewCertPool()
+	for _, certificate := range certificates[1:] {
+		intermediates.AddCert(certificate)
+	}
+
+	chains, err := certificates[0].Verify(x509.VerifyOptions{
+		Roots:         v.clientCAs.Pool(),
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("can't verify client certificate: %w", err)
+	}
+	return chains, nil
+}
+
+// configureClientAuth applies the client authentication settings of TLSConfig to tls.Config.
+func (s *Server) configureClientAuth(c TLSConfig, tlsConfig *tls.Config) error {
+	tlsConfig.ClientAuth = c.ClientAuth
+	tlsConfig.ClientCAs = c.ClientCAs
+	tlsConfig.VerifyPeerCertificate = c.VerifyPeerCertificate
+	tlsConfig.VerifyConnection = c.VerifyConnection
+
+	verifier := clientVerifier{revocation: c.Revocation, connection: c.VerifyConnection}
+
+	switch {
+	case c.ClientCAFile != "" && c.ClientCAReloadInterval != 0:
+		clientCAs, err := certs.NewCAFile(certs.CAFileConfig{
+			File:     c.ClientCAFile,
+			Interval: c.ClientCAReloadInterval,
+			Logger:   serverLogger{server: s},
+		})
+		if err != nil {
+			return fmt.Errorf("can't create client CAs provider: %w", err)
+		}
+		s.clientCAs = clientCAs
+		verifier.clientCAs = clientCAs
+
+		// The verification is moved to verifyConnection, while the certificates are still requested
+		// with the CAs loaded at the start listed, crypto/tls doesn't verify them for these modes.
+		tlsConfig.ClientCAs = clientCAs.Pool()
+		switch c.ClientAuth {
+		case tls.VerifyClientCertIfGiven:
+			tlsConfig.ClientAuth = tls.RequestClientCert
+		case tls.RequireAndVerifyClientCert:
+			tlsConfig.ClientAuth = tls.RequireAnyClientCert
+		}
+
+		// VerifyPeerCertificate receives the chains verified against the reloaded pool.
+		tlsConfig.VerifyPeerCertificate = nil
+		verifier.verify = c.VerifyPeerCertificate
+	case c.ClientCAFile != "":
+		clientCAs, err := loadCertPool(c.ClientCAFile)
+		if err != nil {
+			return err
+		}
+		tlsConfig.ClientCAs = clientCAs
+	}
+
+	if verifier.clientCAs != nil || verifier.revocation != nil {
+		tlsConfig.VerifyConnection = verifier.verifyConnection
+	}
+	return nil
+}
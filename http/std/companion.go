@@ -0,0 +1,100 @@
+package server
+
+import (
+	"context"
+	"github.com/golang-mixins/servers"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// companion is the server, which is served and stopped together with the main one on a separate address.
+type companion struct {
+	server   *http.Server
+	listener net.Listener
+}
+
+// addCompanion adds the companion server of the handler on the address.
+func (s *Server) addCompanion(addr string, handler http.Handler) {
+	s.companions = append(s.companions, &companion{
+		server: &http.Server{
+			Addr:              addr,
+			Handler:           handler,
+			ErrorLog:          s.http.ErrorLog,
+			ReadHeaderTimeout: s.http.ReadHeaderTimeout,
+			IdleTimeout:       s.http.IdleTimeout,
+		},
+	})
+}
+
+// listenCompanions binds the listeners of the companion servers, the bound ones are closed, if any of them fails.
+func (s *Server) listenCompanions() error {
+	for i, companion := range s.companions {
+		listener, err := s.listenConfig.Listen(context.Background(), "tcp", companion.server.Addr)
+		if err != nil {
+			s.closeCompanions(s.companions[:i])
+			return &servers.BindError{Addr: companion.server.Addr, Err: err}
+		}
+		companion.listener = listener
+		s.log().Info("companion listening", "addr", listener.Addr())
+	}
+	return nil
+}
+
+// closeCompanions closes the listeners of the companion servers.
+func (s *Server) closeCompanions(companions []*companion) {
+	for _, companion := range companions {
+		if companion.listener != nil {
+			companion.listener.Close()
+		}
+	}
+}
+
+// serveCompanions serving the companion servers on their listeners bound by Listen.
+func (s *Server) serveCompanions() {
+	for _, companion := range s.companions {
+		go func(server *http.Server, listener net.Listener) {
+			err := server.Serve(listener)
+			if err != nil && err != http.ErrServerClosed {
+				s.log().Error("error companion Serve", "addr", server.Addr, "error", err)
+			}
+		}(companion.server, companion.listener)
+	}
+}
+
+// stopCompanions stops the companion servers and closes their listeners, even if they aren't served.
+// Once ctx is done, e.g. by the drain of the main server, they are shut down until the deadline of the forced close
+// of the main server (or within forceCloseBudget, if it isn't forced), so that Stop isn't prolonged by them.
+func (s *Server) stopCompanions(ctx context.Context, deadline time.Time) {
+	if ctx.Err() != nil {
+		if deadline.IsZero() {
+			deadline = time.Now().Add(servers.ForceCloseBudget(ctx))
+		}
+
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(context.WithoutCancel(ctx), deadline)
+		defer cancel()
+	}
+
+	wg := new(sync.WaitGroup)
+	wg.Add(len(s.companions))
+	for _, companion := range s.companions {
+		go func(server *http.Server) {
+			defer wg.Done()
+
+			err := server.Shutdown(ctx)
+			if err == nil {
+				return
+			}
+			s.log().Error("companion shutdown error", "addr", server.Addr, "error", err)
+
+			if err = server.Close(); err != nil {
+				s.log().Error("companion closing error", "addr", server.Addr, "error", err)
+			}
+		}(companion.server)
+	}
+	wg.Wait()
+
+	s.closeCompanions(s.companions)
+}
@@ -0,0 +1,160 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"sync"
+)
+
+// ConnLimitConfig delivers a set of settings for the per client IP connection limit.
+// PerIP bounds the simultaneous connections of a client IP, the excess connections are closed once accepted.
+// Allowlist holds the IPs and the CIDR prefixes (e.g. of the trusted frontends), which aren't limited.
+// The connections not over TCP (e.g. over the unix domain socket) aren't limited.
+type ConnLimitConfig struct {
+	PerIP     int      `config:"per_ip"`
+	Allowlist []string `config:"allowlist"`
+}
+
+// Validate validates ConnLimitConfig according to predefined rules.
+func (c ConnLimitConfig) Validate() error {
+	var errs []error
+
+	if c.PerIP <= 0 {
+		errs = append(errs, errors.New("PerIP must be positive"))
+	}
+
+	if _, err := parsePrefixes(c.Allowlist); err != nil {
+		errs = append(errs, fmt.Errorf("Allowlist: %w", err))
+	}
+	return errors.Join(errs...)
+}
+
+// parsePrefixes parses the IPs and the CIDR prefixes, an IP is the prefix of its full length.
+func parsePrefixes(values []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(values))
+	for _, value := range values {
+		if addr, err := netip.ParseAddr(value); err == nil {
+			prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(value)
+		if err != nil {
+			return nil, fmt.Errorf("%q is neither IP nor CIDR prefix", value)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+// containsAddr reports whether any of the prefixes contains the address.
+func containsAddr(prefixes []netip.Prefix, addr netip.Addr) bool {
+	for _, prefix := range prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// remoteAddr returns the IP of the remote TCP address, false is returned for the rest of the addresses.
+func remoteAddr(conn net.Conn) (netip.Addr, bool) {
+	addr, ok := conn.RemoteAddr().(*net.TCPAddr)
+	if !ok {
+		return netip.Addr{}, false
+	}
+	return addr.AddrPort().Addr().Unmap(), true
+}
+
+// connLimiter counts the connections per client IP, it is shared by all the listeners of the server.
+type connLimiter struct {
+	perIP     int
+	allowlist []netip.Prefix
+	mutex     *sync.Mutex
+	conns     map[netip.Addr]int
+}
+
+// acquire counts the connection of the address, false is returned, if the limit is exceeded.
+func (l *connLimiter) acquire(addr netip.Addr) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.conns[addr] >= l.perIP {
+		return false
+	}
+	l.conns[addr]++
+	return true
+}
+
+// release uncounts the connection of the address.
+func (l *connLimiter) release(addr netip.Addr) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.conns[addr]--
+	if l.conns[addr] == 0 {
+		delete(l.conns, addr)
+	}
+}
+
+// wrap returns the listener limited by connLimiter.
+func (l *connLimiter) wrap(listener net.Listener) net.Listener {
+	return limitedListener{Listener: listener, limiter: l}
+}
+
+// newConnLimiter - constructor connLimiter.
+func newConnLimiter(c ConnLimitConfig) *connLimiter {
+	allowlist, _ := parsePrefixes(c.Allowlist)
+
+	return &connLimiter{
+		perIP:     c.PerIP,
+		allowlist: allowlist,
+		mutex:     new(sync.Mutex),
+		conns:     make(map[netip.Addr]int),
+	}
+}
+
+// limitedListener closes the accepted connections exceeding the limit of their client IP.
+type limitedListener struct {
+	net.Listener
+	limiter *connLimiter
+}
+
+// Accept waits for the next connection within the limit.
+func (l limitedListener) Accept() (net.Conn, error) {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+
+		addr, ok := remoteAddr(conn)
+		if !ok || containsAddr(l.limiter.allowlist, addr) {
+			return conn, nil
+		}
+
+		if !l.limiter.acquire(addr) {
+			conn.Close()
+			continue
+		}
+		return &limitedConn{Conn: conn, limiter: l.limiter, addr: addr, once: new(sync.Once)}, nil
+	}
+}
+
+// limitedConn uncounts itself once it is closed.
+type limitedConn struct {
+	net.Conn
+	limiter *connLimiter
+	addr    netip.Addr
+	once    *sync.Once
+}
+
+// Close closes the connection.
+func (c *limitedConn) Close() error {
+	c.once.Do(func() {
+		c.limiter.release(c.addr)
+	})
+	return c.Conn.Close()
+}
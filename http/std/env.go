@@ -0,0 +1,39 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// defaultEnvPrefix prefixes the names of the environment variables read by FromEnv.
+const defaultEnvPrefix = "SERVER_"
+
+// FromEnv returns DefaultConfig serving the router on ":8080", which is overridden by the environment variables
+// named by the prefix ("SERVER_" by default) and the setting in upper snake case: SERVER_ADDR, SERVER_READ_TIMEOUT
+// (in the format of time.ParseDuration), SERVER_MAX_HEADER_BYTES, SERVER_KEEP_ALIVE and so on.
+// The unset variables keep the defaults, the malformed ones are reported all together.
+func FromEnv(prefix string, router http.Handler) (Config, error) {
+	if prefix == "" {
+		prefix = defaultEnvPrefix
+	}
+
+	cfg := DefaultConfig(":8080", router)
+
+	var errs []error
+	for _, setting := range cfg.settings() {
+		name := prefix + strings.ToUpper(strings.ReplaceAll(setting.name, "-", "_"))
+
+		text, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+
+		if err := setting.value.Set(text); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+	return cfg, errors.Join(errs...)
+}
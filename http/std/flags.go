@@ -0,0 +1,14 @@
+package server
+
+import (
+	"flag"
+)
+
+// RegisterFlags registers the flags setting the scalar fields of the config in the flag set:
+// -addr, -read-timeout, -stop-timeout, -max-header-bytes, -keep-alive and so on, the same as FromEnv reads.
+// The current values of the fields are the defaults of the flags, so the config must outlive the parsing.
+func (c *Config) RegisterFlags(fs *flag.FlagSet) {
+	for _, setting := range c.settings() {
+		fs.Var(setting.value, setting.name, setting.usage)
+	}
+}
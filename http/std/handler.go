@@ -0,0 +1,64 @@
+package server
+
+import (
+	"net/http"
+)
+
+// handler assembles the handler of the server around Router.
+// The limits apply to Router only, so that the health endpoints keep responding under load.
+func (s *Server) handler(cfg Config) http.Handler {
+	handler := cfg.Router
+
+	if cfg.HandlerTimeout > 0 {
+		handler = http.TimeoutHandler(handler, cfg.HandlerTimeout, http.StatusText(http.StatusServiceUnavailable))
+	}
+
+	if cfg.MaxRequestBodyBytes > 0 {
+		handler = http.MaxBytesHandler(handler, cfg.MaxRequestBodyBytes)
+	}
+
+	if cfg.MaxConcurrentRequests > 0 {
+		handler = limitConcurrency(cfg.MaxConcurrentRequests, handler)
+	}
+
+	if cfg.RateLimit != nil {
+		handler = newRateLimiter(*cfg.RateLimit).wrap(handler)
+	}
+
+	if s.shedder != nil {
+		handler = s.shedder.wrap(handler)
+	}
+
+	handler = s.maintenanceMode(cfg.MaintenanceRetryAfter, handler)
+
+	if cfg.Health != nil {
+		handler = s.configureHealth(*cfg.Health, handler)
+	}
+
+	if cfg.RecoverPanics {
+		handler = s.recoverPanics(handler)
+	}
+
+	if cfg.SecurityHeaders != nil {
+		handler = securityHeaders(*cfg.SecurityHeaders, handler)
+	}
+
+	if cfg.AccessLog != nil {
+		handler = accessLog(cfg.AccessLog, handler)
+	}
+
+	if cfg.RequestID != nil {
+		handler = requestID(*cfg.RequestID, handler)
+	}
+
+	if len(cfg.TrustedProxies) != 0 {
+		trusted, _ := parsePrefixes(cfg.TrustedProxies)
+		handler = realIP(trusted, handler)
+	}
+
+	if cfg.ProxyProtocol != nil {
+		handler = proxyRemoteAddr(handler)
+	}
+
+	return s.tracker.wrap(handler)
+}
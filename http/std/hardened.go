@@ -0,0 +1,74 @@
+package server
+
+import (
+	"github.com/golang-mixins/servers"
+	"net/http"
+	"os"
+	"time"
+)
+
+// The hardened defaults, which resist slowloris and the oversized headers.
+const (
+	hardenedReadHeaderTimeout = 5 * time.Second
+	hardenedIdleTimeout       = 2 * time.Minute
+	hardenedMaxHeaderBytes    = 64 << 10
+)
+
+// Hardened returns Config with zero ReadHeaderTimeout, IdleTimeout and MaxHeaderBytes set to the hardened
+// defaults (5 seconds, 2 minutes and 64 KiB) instead of the unlimited (or 1 MiB) defaults of net/http.
+func (c Config) Hardened() Config {
+	if c.ReadHeaderTimeout == 0 {
+		c.ReadHeaderTimeout = hardenedReadHeaderTimeout
+	}
+	if c.IdleTimeout == 0 {
+		c.IdleTimeout = hardenedIdleTimeout
+	}
+	if c.MaxHeaderBytes == 0 {
+		c.MaxHeaderBytes = hardenedMaxHeaderBytes
+	}
+	return c
+}
+
+// NewHardened - constructor Server with the hardened defaults applied to Config.
+func NewHardened(cfg Config) (*Server, error) {
+	return New(cfg.Hardened())
+}
+
+// The defaults of DefaultConfig on top of the hardened ones.
+const (
+	defaultReadTimeout  = 30 * time.Second
+	defaultWriteTimeout = 30 * time.Second
+	defaultStopTimeout  = 30 * time.Second
+)
+
+// DefaultConfig returns Config serving the router on addr with the production safe defaults: the hardened ones,
+// 30 seconds ReadTimeout, WriteTimeout and StopTimeout, enabled keep-alives and Logger writing to os.Stderr.
+func DefaultConfig(addr string, router http.Handler) Config {
+	return Config{
+		Addr:             addr,
+		Router:           router,
+		ReadTimeout:      defaultReadTimeout,
+		WriteTimeout:     defaultWriteTimeout,
+		StopTimeout:      defaultStopTimeout,
+		KeepAliveEnabled: true,
+		Logger:           servers.NewLogger(os.Stderr, "Golang HTTP standard server: "),
+	}.Hardened()
+}
+
+// WithDevTLS returns Config serving HTTPS with the self-signed certificate for localhost generated in memory,
+// so that HTTPS is served locally without issuing certificates. The rest of TLS settings, if any, are kept.
+func (c Config) WithDevTLS() Config {
+	tlsConfig := TLSConfig{}
+	if c.TLS != nil {
+		tlsConfig = *c.TLS
+	}
+	tlsConfig.CertFile = ""
+	tlsConfig.KeyFile = ""
+	tlsConfig.ReloadInterval = 0
+	tlsConfig.Autocert = nil
+	tlsConfig.Provider = nil
+	tlsConfig.SelfSigned = true
+
+	c.TLS = &tlsConfig
+	return c
+}
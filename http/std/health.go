@@ -0,0 +1,129 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"github.com/golang-mixins/servers"
+	"net/http"
+	"strings"
+)
+
+// HealthConfig delivers a set of settings for the health endpoints of the server.
+// Addr, if set, serves the endpoints on a separate port, otherwise they are served alongside Router.
+// Empty paths default to /livez, /readyz and /healthz (an alias of liveness).
+// Liveness fails once the server has failed, readiness succeeds only while the server is serving,
+// so it fails while draining and stopping. Checks, if set, are aggregated into readiness as well.
+// MaintenanceNotReady fails readiness, while the server is in maintenance mode.
+type HealthConfig struct {
+	Addr                string `config:"addr"`
+	LivenessPath        string `config:"liveness_path"`
+	ReadinessPath       string `config:"readiness_path"`
+	HealthPath          string `config:"health_path"`
+	Checks              *servers.HealthRegistry
+	MaintenanceNotReady bool `config:"maintenance_not_ready"`
+}
+
+// Validate validates HealthConfig according to predefined rules.
+func (c HealthConfig) Validate() error {
+	var errs []error
+
+	if c.Addr != "" {
+		if err := servers.ValidateAddr(c.Addr); err != nil {
+			errs = append(errs, fmt.Errorf("Addr: %w", err))
+		}
+	}
+
+	for _, path := range []string{c.LivenessPath, c.ReadinessPath, c.HealthPath} {
+		if path != "" && !strings.HasPrefix(path, "/") {
+			errs = append(errs, fmt.Errorf("path %q must start with /", path))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// withDefaults returns HealthConfig with the empty paths set to the defaults.
+func (c HealthConfig) withDefaults() HealthConfig {
+	if c.LivenessPath == "" {
+		c.LivenessPath = "/livez"
+	}
+	if c.ReadinessPath == "" {
+		c.ReadinessPath = "/readyz"
+	}
+	if c.HealthPath == "" {
+		c.HealthPath = "/healthz"
+	}
+	return c
+}
+
+// errMaintenance is reported by readiness in maintenance mode.
+var errMaintenance = errors.New("maintenance")
+
+// health predetermines the consistency of the handler serving the health endpoints of the server.
+// The requests to other paths are passed to next, if it isn't nil.
+type health struct {
+	server *Server
+	config HealthConfig
+	next   http.Handler
+}
+
+// ServeHTTP serves the health endpoints.
+func (h health) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case h.config.LivenessPath, h.config.HealthPath:
+		state := h.server.State()
+		h.respond(w, state != StateFailed, state)
+	case h.config.ReadinessPath:
+		state := h.server.State()
+		if state == StateServing && h.config.MaintenanceNotReady && h.server.InMaintenance() {
+			h.respondError(w, errMaintenance)
+			return
+		}
+
+		if state != StateServing || h.config.Checks == nil {
+			h.respond(w, state == StateServing, state)
+			return
+		}
+
+		if err := h.config.Checks.Check(r.Context()).Err(); err != nil {
+			h.respondError(w, err)
+			return
+		}
+		h.respond(w, true, state)
+	default:
+		if h.next == nil {
+			http.NotFound(w, r)
+			return
+		}
+		h.next.ServeHTTP(w, r)
+	}
+}
+
+// respond writes the result of the check.
+func (h health) respond(w http.ResponseWriter, ok bool, state State) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+
+	if !ok {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	w.Write([]byte(state.String()))
+}
+
+// respondError writes the failed checks.
+func (h health) respondError(w http.ResponseWriter, err error) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+
+	w.WriteHeader(http.StatusServiceUnavailable)
+	w.Write([]byte(err.Error()))
+}
+
+// configureHealth mounts the health endpoints alongside the handler, or on the companion server.
+func (s *Server) configureHealth(c HealthConfig, handler http.Handler) http.Handler {
+	c = c.withDefaults()
+	if c.Addr != "" {
+		s.addCompanion(c.Addr, health{server: s, config: c})
+		return handler
+	}
+	return health{server: s, config: c, next: handler}
+}
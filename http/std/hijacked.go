@@ -0,0 +1,125 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"github.com/golang-mixins/servers"
+	"net"
+	"sync"
+	"time"
+)
+
+// HijackedPolicy is the policy of Stop towards the hijacked connections (e.g. WebSockets),
+// which http.Server.Shutdown neither waits for nor closes.
+type HijackedPolicy int
+
+const (
+	// HijackedIgnore leaves the hijacked connections to their handlers, as net/http does.
+	HijackedIgnore HijackedPolicy = iota
+	// HijackedWait waits for the hijacked connections to be closed by their handlers within the drain,
+	// the rest are closed forcibly and reported by the error of Stop. The handlers are meant to be notified
+	// by Hooks.OnStopping, e.g. to send the WebSocket close frame.
+	HijackedWait
+	// HijackedClose closes the hijacked connections, once the rest of the connections are drained.
+	HijackedClose
+)
+
+// hijackedPollInterval is the interval of checking the hijacked connections are closed, while Stop waits for them.
+const hijackedPollInterval = 100 * time.Millisecond
+
+// trackedConn reports its closing to the tracker, so that the hijacked connections are tracked until closed.
+type trackedConn struct {
+	net.Conn
+	tracker *tracker
+	once    *sync.Once
+	opened  time.Time
+}
+
+// Close closes the connection.
+func (c *trackedConn) Close() error {
+	c.once.Do(func() {
+		c.tracker.closed(c)
+	})
+	return c.Conn.Close()
+}
+
+// trackedListener wraps the accepted connections into trackedConn.
+type trackedListener struct {
+	net.Listener
+	tracker *tracker
+}
+
+// Accept waits for and returns the next connection.
+func (l trackedListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return &trackedConn{Conn: conn, tracker: l.tracker, once: new(sync.Once), opened: time.Now()}, nil
+}
+
+// hijackedConn returns the tracked connection underlying the hijacked one, if it is tracked.
+func hijackedConn(conn net.Conn) (*trackedConn, bool) {
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		conn = tlsConn.NetConn()
+	}
+	tracked, ok := conn.(*trackedConn)
+	return tracked, ok
+}
+
+// closed stops tracking the hijacked connection.
+func (t *tracker) closed(conn *trackedConn) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	delete(t.hijackedConns, conn)
+}
+
+// openHijacked returns the number of the hijacked connections, which aren't closed yet.
+func (t *tracker) openHijacked() int {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return len(t.hijackedConns)
+}
+
+// closeHijacked closes the hijacked connections, which aren't closed yet, and returns their number.
+func (t *tracker) closeHijacked() int {
+	t.mutex.Lock()
+	conns := make([]*trackedConn, 0, len(t.hijackedConns))
+	for conn := range t.hijackedConns {
+		conns = append(conns, conn)
+	}
+	t.mutex.Unlock()
+
+	for _, conn := range conns {
+		conn.Close()
+	}
+	return len(conns)
+}
+
+// stopHijacked applies the policy to the hijacked connections, once the rest of the connections are drained.
+func (s *Server) stopHijacked(ctx context.Context) error {
+	switch s.hijacked {
+	case HijackedWait:
+		ticker := time.NewTicker(hijackedPollInterval)
+		defer ticker.Stop()
+
+		for s.tracker.openHijacked() != 0 {
+			select {
+			case <-ctx.Done():
+				if open := s.tracker.openHijacked(); open != 0 {
+					return fmt.Errorf("%d hijacked connections open: %w", open, servers.ErrShutdownTimeout)
+				}
+				return nil
+			case <-ticker.C:
+			}
+		}
+	case HijackedClose:
+		if closed := s.tracker.closeHijacked(); closed != 0 {
+			s.log().Info("hijacked connections closed", "count", closed)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package server
+
+import (
+	"context"
+	"net"
+	"time"
+)
+
+// Hooks delivers a set of callbacks invoked on the lifecycle events of the server, nil callbacks are skipped.
+// OnStart is called once serving begins, OnStopping once shutdown starts (ctx bounds the shutdown),
+// OnStop once shutdown ends and OnError once Serve exits with an error or a panic of the handler is recovered
+// (see Config.RecoverPanics).
+// OnDrainProgress is called every Config.StopProgressInterval, while the server drains, with the remaining
+// connections and requests and the time elapsed since Stop.
+// OnForceClose is called with the connections remaining, once the drain is exceeded, before they are closed.
+// OnPhase is called on entering each phase of the shutdown, the phase starts once it returns (ctx bounds
+// the shutdown, except of PhasePostStop), see ShutdownPhase.
+type Hooks struct {
+	OnStart         func(addr net.Addr)
+	OnStopping      func(ctx context.Context)
+	OnStop          func(err error)
+	OnError         func(err error)
+	OnDrainProgress func(stats Stats, elapsed time.Duration)
+	OnForceClose    func(stragglers []Straggler)
+	OnPhase         func(ctx context.Context, phase ShutdownPhase)
+}
+
+// start invokes OnStart.
+func (h Hooks) start(addr net.Addr) {
+	if h.OnStart != nil {
+		h.OnStart(addr)
+	}
+}
+
+// stopping invokes OnStopping.
+func (h Hooks) stopping(ctx context.Context) {
+	if h.OnStopping != nil {
+		h.OnStopping(ctx)
+	}
+}
+
+// stop invokes OnStop.
+func (h Hooks) stop(err error) {
+	if h.OnStop != nil {
+		h.OnStop(err)
+	}
+}
+
+// drainProgress invokes OnDrainProgress.
+func (h Hooks) drainProgress(stats Stats, elapsed time.Duration) {
+	if h.OnDrainProgress != nil {
+		h.OnDrainProgress(stats, elapsed)
+	}
+}
+
+// forceClose invokes OnForceClose.
+func (h Hooks) forceClose(stragglers []Straggler) {
+	if h.OnForceClose != nil {
+		h.OnForceClose(stragglers)
+	}
+}
+
+// phase invokes OnPhase.
+func (h Hooks) phase(ctx context.Context, phase ShutdownPhase) {
+	if h.OnPhase != nil {
+		h.OnPhase(ctx, phase)
+	}
+}
+
+// error invokes OnError, if err isn't nil.
+func (h Hooks) error(err error) {
+	if h.OnError != nil && err != nil {
+		h.OnError(err)
+	}
+}
@@ -0,0 +1,49 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"golang.org/x/net/http2"
+	"time"
+)
+
+// HTTP2Config delivers a set of HTTP/2 settings for server implementation.
+// Zero values keep the defaults of golang.org/x/net/http2.
+type HTTP2Config struct {
+	MaxConcurrentStreams uint32        `config:"max_concurrent_streams"`
+	MaxReadFrameSize     uint32        `config:"max_read_frame_size"`
+	IdleTimeout          time.Duration `config:"idle_timeout"`
+	WriteByteTimeout     time.Duration `config:"write_byte_timeout"`
+}
+
+// Validate validates HTTP2Config according to predefined rules.
+func (c HTTP2Config) Validate() error {
+	var errs []error
+
+	if c.MaxReadFrameSize != 0 && (c.MaxReadFrameSize < 1<<14 || c.MaxReadFrameSize > 1<<24-1) {
+		errs = append(errs, errors.New("MaxReadFrameSize must be between 16384 and 16777215"))
+	}
+
+	if c.IdleTimeout < 0 {
+		errs = append(errs, errors.New("IdleTimeout can't be negative"))
+	}
+
+	if c.WriteByteTimeout < 0 {
+		errs = append(errs, errors.New("WriteByteTimeout can't be negative"))
+	}
+	return errors.Join(errs...)
+}
+
+// configureHTTP2 applies HTTP2Config to the server.
+func (s *Server) configureHTTP2(c HTTP2Config) error {
+	err := http2.ConfigureServer(s.http, &http2.Server{
+		MaxConcurrentStreams: c.MaxConcurrentStreams,
+		MaxReadFrameSize:     c.MaxReadFrameSize,
+		IdleTimeout:          c.IdleTimeout,
+		WriteByteTimeout:     c.WriteByteTimeout,
+	})
+	if err != nil {
+		return fmt.Errorf("can't configure http2: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+)
+
+// IPFilterConfig delivers a set of settings for the filtering of the connections by the peer IP at accept time.
+// Allow and Deny hold the IPs and the CIDR prefixes. Deny takes precedence, non-empty Allow admits only the peers
+// it contains. The connections not over TCP (e.g. over the unix domain socket) aren't filtered.
+type IPFilterConfig struct {
+	Allow []string `config:"allow"`
+	Deny  []string `config:"deny"`
+}
+
+// Validate validates IPFilterConfig according to predefined rules.
+func (c IPFilterConfig) Validate() error {
+	var errs []error
+
+	if len(c.Allow) == 0 && len(c.Deny) == 0 {
+		errs = append(errs, errors.New("at least one of Allow and Deny must be set"))
+	}
+
+	if _, err := parsePrefixes(c.Allow); err != nil {
+		errs = append(errs, fmt.Errorf("Allow: %w", err))
+	}
+
+	if _, err := parsePrefixes(c.Deny); err != nil {
+		errs = append(errs, fmt.Errorf("Deny: %w", err))
+	}
+	return errors.Join(errs...)
+}
+
+// filteredListener closes the accepted connections of the peers rejected by the filter.
+type filteredListener struct {
+	net.Listener
+	allow []netip.Prefix
+	deny  []netip.Prefix
+}
+
+// Accept waits for the next admitted connection.
+func (l filteredListener) Accept() (net.Conn, error) {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+
+		if l.admits(conn) {
+			return conn, nil
+		}
+		conn.Close()
+	}
+}
+
+// admits reports whether the connection passes the filter.
+func (l filteredListener) admits(conn net.Conn) bool {
+	addr, ok := remoteAddr(conn)
+	if !ok {
+		return true
+	}
+
+	if containsAddr(l.deny, addr) {
+		return false
+	}
+	return len(l.allow) == 0 || containsAddr(l.allow, addr)
+}
+
+// newFilteredListener - constructor filteredListener.
+func newFilteredListener(listener net.Listener, c IPFilterConfig) filteredListener {
+	allow, _ := parsePrefixes(c.Allow)
+	deny, _ := parsePrefixes(c.Deny)
+
+	return filteredListener{Listener: listener, allow: allow, deny: deny}
+}
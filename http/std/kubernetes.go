@@ -0,0 +1,102 @@
+package server
+
+import (
+	"errors"
+	"github.com/golang-mixins/servers"
+	"time"
+)
+
+// defaultTerminationGracePeriod is terminationGracePeriodSeconds of the pod by default of Kubernetes.
+const defaultTerminationGracePeriod = 30 * time.Second
+
+// KubernetesShutdownConfig delivers a set of settings for the shutdown budget of the pod.
+// TerminationGracePeriod is terminationGracePeriodSeconds of the pod (30 seconds by default), the container
+// is killed by SIGKILL once it elapses after SIGTERM.
+// PreStopDelay is the time for the endpoints of the pod to be removed from the load balancers, while the server
+// keeps serving, it is counted in the budget unlike the preStop hook of the pod (which SIGTERM waits for).
+// DrainTimeout is the desired time to drain the connections, the rest of the budget is used, if it isn't set.
+// Margin is the part of the budget kept for the forced close of the stragglers and the exit of the process
+// (a tenth of TerminationGracePeriod by default).
+type KubernetesShutdownConfig struct {
+	TerminationGracePeriod time.Duration
+	PreStopDelay           time.Duration
+	DrainTimeout           time.Duration
+	Margin                 time.Duration
+}
+
+// Validate validates KubernetesShutdownConfig according to predefined rules.
+func (c KubernetesShutdownConfig) Validate() error {
+	var errs []error
+
+	if c.TerminationGracePeriod < 0 {
+		errs = append(errs, errors.New("TerminationGracePeriod can't be negative"))
+	}
+
+	if c.PreStopDelay < 0 {
+		errs = append(errs, errors.New("PreStopDelay can't be negative"))
+	}
+
+	if c.DrainTimeout < 0 {
+		errs = append(errs, errors.New("DrainTimeout can't be negative"))
+	}
+
+	if c.Margin < 0 {
+		errs = append(errs, errors.New("Margin can't be negative"))
+	}
+	return errors.Join(errs...)
+}
+
+// WithKubernetesShutdown returns Config with PreStopDelay, DrainTimeout and StopTimeout composed so that the shutdown
+// (PreStopDelay, StopTimeout and the forced close after it) never exceeds TerminationGracePeriod less Margin:
+// the forced close takes at most a second past StopTimeout (see Stop), so the second is reserved of the budget.
+// If the budget is impossible, it is logged to Logger (unless it is nil) and the delays are cut: the budget
+// to the half of TerminationGracePeriod, PreStopDelay to the half of the budget, DrainTimeout to StopTimeout.
+// The invalid k is logged and leaves Config unchanged.
+func (c Config) WithKubernetesShutdown(k KubernetesShutdownConfig) Config {
+	warn := func(msg string, keysAndValues ...interface{}) {
+		if c.Logger != nil {
+			c.Logger.Error(msg, keysAndValues...)
+		}
+	}
+
+	if err := k.Validate(); err != nil {
+		warn("invalid kubernetes shutdown config", "error", err)
+		return c
+	}
+
+	grace := k.TerminationGracePeriod
+	if grace == 0 {
+		grace = defaultTerminationGracePeriod
+	}
+	margin := k.Margin
+	if margin == 0 {
+		margin = grace / 10
+	}
+
+	budget := grace - margin - servers.ForceCloseTimeout
+	if budget <= 0 {
+		warn("shutdown budget is impossible, margin and forced close exceed termination grace period",
+			"termination_grace_period", grace, "margin", margin, "force_close", servers.ForceCloseTimeout)
+		budget = grace / 2
+	}
+
+	preStop := k.PreStopDelay
+	if preStop >= budget {
+		warn("shutdown budget is impossible, pre-stop delay leaves no time to drain",
+			"budget", budget, "pre_stop_delay", preStop, "cut_to", budget/2)
+		preStop = budget / 2
+	}
+
+	stopTimeout := budget - preStop
+	drainTimeout := k.DrainTimeout
+	if drainTimeout > stopTimeout {
+		warn("shutdown budget is impossible, drain timeout exceeds the time left after pre-stop delay",
+			"budget", budget, "pre_stop_delay", preStop, "drain_timeout", drainTimeout, "cut_to", stopTimeout)
+		drainTimeout = stopTimeout
+	}
+
+	c.PreStopDelay = preStop
+	c.StopTimeout = stopTimeout
+	c.DrainTimeout = drainTimeout
+	return c
+}
@@ -0,0 +1,24 @@
+package server
+
+import (
+	"net/http"
+)
+
+// limitConcurrency wraps the handler serving at most limit requests at once, the excess is rejected
+// with 503 and Retry-After instead of queueing.
+func limitConcurrency(limit int, handler http.Handler) http.Handler {
+	semaphore := make(chan struct{}, limit)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case semaphore <- struct{}{}:
+			defer func() {
+				<-semaphore
+			}()
+			handler.ServeHTTP(w, r)
+		default:
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		}
+	})
+}
@@ -0,0 +1,139 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"github.com/golang-mixins/servers"
+	"net"
+	"strings"
+	"syscall"
+)
+
+// unixScheme prefixes Addr, which is a path of the unix domain socket.
+const unixScheme = "unix://"
+
+// validateAddr validates the address, which is either "host:port" or the prefixed path of the unix domain socket.
+func validateAddr(addr string) error {
+	if path, ok := strings.CutPrefix(addr, unixScheme); ok {
+		if path == "" {
+			return fmt.Errorf("invalid address %q: empty socket path", addr)
+		}
+		return nil
+	}
+	return servers.ValidateAddr(addr)
+}
+
+// listen binds the listeners according to the server addresses, unless the listener is injected.
+func (s *Server) listen() ([]net.Listener, error) {
+	if s.listener != nil {
+		return []net.Listener{s.listener}, nil
+	}
+
+	if s.reusePort > 0 {
+		return s.listenReusePort(s.http.Addr, s.reusePort)
+	}
+
+	listeners := make([]net.Listener, 0, len(s.addrs))
+	for _, addr := range s.addrs {
+		listener, err := s.listenAddr(addr)
+		if err != nil {
+			for _, listener := range listeners {
+				listener.Close()
+			}
+			return nil, err
+		}
+		listeners = append(listeners, listener)
+	}
+	return listeners, nil
+}
+
+// listenAddr binds the listener on the address, which is either tcp or prefixed unix domain socket one.
+func (s *Server) listenAddr(addr string) (net.Listener, error) {
+	network, address := "tcp", addr
+	if strings.HasPrefix(address, unixScheme) {
+		network, address = "unix", strings.TrimPrefix(address, unixScheme)
+		if err := servers.RemoveStaleSocket(address); err != nil {
+			return nil, err
+		}
+	}
+
+	listener, err := s.listenConfig.Listen(context.Background(), network, address)
+	if err != nil {
+		return nil, fmt.Errorf("can't listen %s %s: %w", network, address, err)
+	}
+	return listener, nil
+}
+
+// wrapListeners applies the listener level settings to the bound listeners.
+// The filter goes first, so that the rejected connections aren't counted by the limit,
+// the gate goes last, so that Stop closes the listeners in PhaseStopAccepting.
+func (s *Server) wrapListeners(listeners []net.Listener) []net.Listener {
+	if s.ipFilter != nil {
+		for i, listener := range listeners {
+			listeners[i] = newFilteredListener(listener, *s.ipFilter)
+		}
+	}
+
+	if s.connLimiter != nil {
+		for i, listener := range listeners {
+			listeners[i] = s.connLimiter.wrap(listener)
+		}
+	}
+
+	if s.proxyProtocol != nil {
+		for i, listener := range listeners {
+			listeners[i] = newProxyListener(listener, *s.proxyProtocol)
+		}
+	}
+
+	if s.hijacked != HijackedIgnore {
+		for i, listener := range listeners {
+			listeners[i] = trackedListener{Listener: listener, tracker: s.tracker}
+		}
+	}
+
+	for i, listener := range listeners {
+		listeners[i] = newGateListener(listener)
+	}
+	return listeners
+}
+
+// listenReusePort binds count listeners with SO_REUSEPORT on the same address,
+// so the kernel balances the accepted connections between them.
+// The ephemeral port bound by the first listener is shared by the rest.
+func (s *Server) listenReusePort(address string, count int) ([]net.Listener, error) {
+	config := s.listenConfig
+	config.Control = chainControls(reusePortControl, s.listenConfig.Control)
+
+	listeners := make([]net.Listener, 0, count)
+	for i := 0; i < count; i++ {
+		listener, err := config.Listen(context.Background(), "tcp", address)
+		if err != nil {
+			for _, listener := range listeners {
+				listener.Close()
+			}
+			return nil, fmt.Errorf("can't listen tcp %s with SO_REUSEPORT: %w", address, err)
+		}
+		listeners = append(listeners, listener)
+		address = listener.Addr().String()
+	}
+	return listeners, nil
+}
+
+// control is called on the socket before it is bound, see net.ListenConfig.
+type control func(network, address string, conn syscall.RawConn) error
+
+// chainControls returns the control calling the non-nil controls in order, until one of them fails.
+func chainControls(controls ...control) control {
+	return func(network, address string, conn syscall.RawConn) error {
+		for _, control := range controls {
+			if control == nil {
+				continue
+			}
+			if err := control(network, address, conn); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,163 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Load returns DefaultConfig serving the router on ":8080", which is overridden by the settings of the file
+// and validated. The format is chosen by the extension of the file: .json, .yaml (.yml) or .toml.
+// The keys are the config tags of Config and of its nested settings (e.g. tls, rate_limit), the durations
+// are in the format of time.ParseDuration, e.g. "30s". The nested settings are allocated, once they are present
+// in the file. The settings without the tag (the handlers, the loggers, the hooks and so on) aren't loaded,
+// they are set in the code. The unknown keys are refused.
+func Load(path string, router http.Handler) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("can't read config: %w", err)
+	}
+
+	values := make(map[string]interface{})
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		err = decoder.Decode(&values)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &values)
+	case ".toml":
+		err = toml.Unmarshal(data, &values)
+	default:
+		return Config{}, fmt.Errorf("unsupported config format %q", ext)
+	}
+	if err != nil {
+		return Config{}, fmt.Errorf("can't decode config: %w", err)
+	}
+
+	cfg := DefaultConfig(":8080", router)
+	if err = errors.Join(decodeStruct(values, reflect.ValueOf(&cfg).Elem(), "")...); err != nil {
+		return Config{}, err
+	}
+
+	if err = cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// decodeStruct sets the fields of the struct tagged by config from the values, the keys of the nested settings
+// are reported with the prefix of their parents, e.g. "tls.cert_file".
+func decodeStruct(values map[string]interface{}, target reflect.Value, prefix string) []error {
+	fields := make(map[string]reflect.Value)
+	for i := 0; i < target.NumField(); i++ {
+		if tag := target.Type().Field(i).Tag.Get("config"); tag != "" {
+			fields[tag] = target.Field(i)
+		}
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var errs []error
+	for _, key := range keys {
+		field, ok := fields[key]
+		if !ok {
+			errs = append(errs, fmt.Errorf("unknown setting %q", prefix+key))
+			continue
+		}
+
+		if field.Kind() == reflect.Pointer && field.Type().Elem().Kind() == reflect.Struct {
+			nested, ok := values[key].(map[string]interface{})
+			if !ok {
+				errs = append(errs, fmt.Errorf("%s%s: must be a table of settings", prefix, key))
+				continue
+			}
+			if field.IsNil() {
+				field.Set(reflect.New(field.Type().Elem()))
+			}
+			errs = append(errs, decodeStruct(nested, field.Elem(), prefix+key+".")...)
+			continue
+		}
+
+		if err := decodeValue(values[key], field); err != nil {
+			errs = append(errs, fmt.Errorf("%s%s: %w", prefix, key, err))
+		}
+	}
+	return errs
+}
+
+// decodeValue sets the scalar or the list of scalars from the decoded value.
+func decodeValue(value interface{}, target reflect.Value) error {
+	if target.Kind() == reflect.Slice {
+		items, ok := value.([]interface{})
+		if !ok {
+			return errors.New("must be a list")
+		}
+
+		slice := reflect.MakeSlice(target.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := decodeValue(item, slice.Index(i)); err != nil {
+				return fmt.Errorf("item %d: %w", i, err)
+			}
+		}
+		target.Set(slice)
+		return nil
+	}
+
+	text := fmt.Sprint(value)
+	if target.Type() == reflect.TypeOf(time.Duration(0)) {
+		duration, err := time.ParseDuration(text)
+		if err != nil {
+			return err
+		}
+		target.SetInt(int64(duration))
+		return nil
+	}
+
+	switch target.Kind() {
+	case reflect.String:
+		target.SetString(text)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(text)
+		if err != nil {
+			return err
+		}
+		target.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(text, 10, target.Type().Bits())
+		if err != nil {
+			return err
+		}
+		target.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(text, 10, target.Type().Bits())
+		if err != nil {
+			return err
+		}
+		target.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(text, target.Type().Bits())
+		if err != nil {
+			return err
+		}
+		target.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported type %s", target.Type())
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"github.com/golang-mixins/servers"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultNoise is the noise of net/http logged on the internet facing servers, e.g. by the scanners.
+var defaultNoise = []string{
+	"http: TLS handshake error",
+	"client disconnected",
+	"error reading preface from client",
+}
+
+// ErrorLogFilterConfig delivers a set of settings for filtering the noise out of the error log of net/http.
+// The messages containing any of Patterns (TLS handshake errors and client disconnections by default) are dropped,
+// unless Interval is positive, then one of them per pattern is logged every Interval with the number
+// of the suppressed ones.
+type ErrorLogFilterConfig struct {
+	Patterns []string      `config:"patterns"`
+	Interval time.Duration `config:"interval"`
+}
+
+// Validate validates ErrorLogFilterConfig according to predefined rules.
+func (c ErrorLogFilterConfig) Validate() error {
+	var errs []error
+
+	for _, pattern := range c.Patterns {
+		if pattern == "" {
+			errs = append(errs, errors.New("Patterns can't contain empty pattern"))
+		}
+	}
+
+	if c.Interval < 0 {
+		errs = append(errs, errors.New("Interval can't be negative"))
+	}
+	return errors.Join(errs...)
+}
+
+// noiseSample is the state of the rate limiting of the pattern.
+type noiseSample struct {
+	logged     time.Time
+	suppressed int
+}
+
+// logFilter predetermines the consistency of the implementation servers.Logger, which filters
+// the unstructured messages of net/http, the rest are passed as is.
+type logFilter struct {
+	servers.Logger
+	patterns []string
+	interval time.Duration
+	mutex    *sync.Mutex
+	samples  map[string]*noiseSample
+}
+
+// Printf logs the message, unless it is filtered.
+func (f logFilter) Printf(format string, v ...interface{}) {
+	msg := fmt.Sprintf(format, v...)
+
+	for _, pattern := range f.patterns {
+		if !strings.Contains(msg, pattern) {
+			continue
+		}
+		if f.interval <= 0 {
+			return
+		}
+
+		f.mutex.Lock()
+		sample, ok := f.samples[pattern]
+		if !ok {
+			sample = new(noiseSample)
+			f.samples[pattern] = sample
+		}
+		if time.Since(sample.logged) < f.interval {
+			sample.suppressed++
+			f.mutex.Unlock()
+			return
+		}
+		suppressed := sample.suppressed
+		sample.logged, sample.suppressed = time.Now(), 0
+		f.mutex.Unlock()
+
+		if suppressed != 0 {
+			msg = fmt.Sprintf("%s (%d similar suppressed)", msg, suppressed)
+		}
+		break
+	}
+
+	f.Logger.Printf("%s", msg)
+}
+
+// newLogFilter - constructor logFilter.
+func newLogFilter(c ErrorLogFilterConfig, logger servers.Logger) logFilter {
+	patterns := c.Patterns
+	if len(patterns) == 0 {
+		patterns = defaultNoise
+	}
+
+	return logFilter{
+		Logger:   logger,
+		patterns: patterns,
+		interval: c.Interval,
+		mutex:    new(sync.Mutex),
+		samples:  make(map[string]*noiseSample, len(patterns)),
+	}
+}
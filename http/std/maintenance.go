@@ -0,0 +1,49 @@
+package server
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// defaultMaintenanceRetryAfter is Retry-After of the responses in maintenance mode by default.
+const defaultMaintenanceRetryAfter = time.Minute
+
+// EnterMaintenance switches the server into maintenance mode: Router isn't called, the requests are answered
+// with 503 and Retry-After (Config.MaintenanceRetryAfter). The health endpoints keep responding,
+// readiness fails in maintenance mode, if HealthConfig.MaintenanceNotReady is set.
+func (s *Server) EnterMaintenance() {
+	if !s.maintenance.Swap(true) {
+		s.log().Info("maintenance mode entered")
+	}
+}
+
+// ExitMaintenance switches the server back from maintenance mode.
+func (s *Server) ExitMaintenance() {
+	if s.maintenance.Swap(false) {
+		s.log().Info("maintenance mode exited")
+	}
+}
+
+// InMaintenance reports whether the server is in maintenance mode.
+func (s *Server) InMaintenance() bool {
+	return s.maintenance.Load()
+}
+
+// maintenanceMode wraps the handler answering 503 with Retry-After in maintenance mode.
+func (s *Server) maintenanceMode(retryAfter time.Duration, handler http.Handler) http.Handler {
+	if retryAfter == 0 {
+		retryAfter = defaultMaintenanceRetryAfter
+	}
+	seconds := strconv.Itoa(int(math.Ceil(retryAfter.Seconds())))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.maintenance.Load() {
+			w.Header().Set("Retry-After", seconds)
+			http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,121 @@
+package server
+
+import (
+	"context"
+	"net"
+	"sync"
+)
+
+// ShutdownPhase represents the phase of the shutdown performed by Stop.
+type ShutdownPhase int
+
+// Phases of the shutdown, in the order they are entered. PhaseForceClose is entered only,
+// if the drain is exceeded.
+const (
+	// PhaseStopAccepting closes the listeners, while the open connections are still served.
+	PhaseStopAccepting ShutdownPhase = iota
+	// PhasePreDrain is the boundary between the listeners closed and the drain started,
+	// e.g. to flush the caches or to deregister the server.
+	PhasePreDrain
+	// PhaseDrain waits for the connections to finish their requests and to become idle.
+	PhaseDrain
+	// PhaseForceClose closes the connections remaining after the drain.
+	PhaseForceClose
+	// PhasePostStop follows the release of the resources and the stop of the companion servers.
+	PhasePostStop
+)
+
+// String returns the name of the phase.
+func (p ShutdownPhase) String() string {
+	switch p {
+	case PhaseStopAccepting:
+		return "stop-accepting"
+	case PhasePreDrain:
+		return "pre-drain"
+	case PhaseDrain:
+		return "drain"
+	case PhaseForceClose:
+		return "force-close"
+	case PhasePostStop:
+		return "post-stop"
+	default:
+		return "unknown"
+	}
+}
+
+// phase enters the phase of the shutdown, the phase starts once Hooks.OnPhase returns.
+func (s *Server) phase(ctx context.Context, phase ShutdownPhase) {
+	s.log().Info("shutdown phase", "phase", phase)
+	s.hooks.phase(ctx, phase)
+}
+
+// gateListener closes the listener ahead of http.Server.Shutdown, so that the new connections are refused,
+// while Serve keeps waiting in Accept until the listener is closed by http.Server.
+type gateListener struct {
+	net.Listener
+	stopped chan struct{}
+	closed  chan struct{}
+	stop    *sync.Once
+	close   *sync.Once
+}
+
+// Accept waits for and returns the next connection.
+func (l gateListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err == nil {
+		return conn, nil
+	}
+
+	select {
+	case <-l.stopped:
+		<-l.closed
+	default:
+	}
+	return nil, err
+}
+
+// stopAccepting closes the underlying listener.
+func (l gateListener) stopAccepting() error {
+	err := net.ErrClosed
+	l.stop.Do(func() {
+		close(l.stopped)
+		err = l.Listener.Close()
+	})
+	return err
+}
+
+// Close closes the listener, unless it is closed by stopAccepting already.
+func (l gateListener) Close() error {
+	err := l.stopAccepting()
+	l.close.Do(func() {
+		close(l.closed)
+	})
+	if err == net.ErrClosed {
+		return nil
+	}
+	return err
+}
+
+// newGateListener - constructor gateListener.
+func newGateListener(listener net.Listener) gateListener {
+	return gateListener{
+		Listener: listener,
+		stopped:  make(chan struct{}),
+		closed:   make(chan struct{}),
+		stop:     new(sync.Once),
+		close:    new(sync.Once),
+	}
+}
+
+// stopAccepting closes the listeners of the server, the open connections are still served.
+func (s *Server) stopAccepting() {
+	for _, listener := range s.listeners {
+		gate, ok := listener.(gateListener)
+		if !ok {
+			continue
+		}
+		if err := gate.stopAccepting(); err != nil && err != net.ErrClosed {
+			s.log().Error("closing listener error", "addr", gate.Addr(), "error", err)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package server
+
+import (
+	"time"
+)
+
+// reportProgress reports the connections and requests remaining every StopProgressInterval, while the server drains,
+// until the returned function is called.
+func (s *Server) reportProgress(started time.Time) func() {
+	if s.progress <= 0 {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(s.progress)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				stats := s.Stats()
+				elapsed := time.Since(started)
+				s.log().Info("draining",
+					"open_connections", stats.OpenConnections,
+					"active_requests", stats.ActiveRequests,
+					"open_hijacked_connections", stats.OpenHijackedConnections,
+					"elapsed", elapsed)
+				s.hooks.drainProgress(stats, elapsed)
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+	}
+}
@@ -0,0 +1,301 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// proxyHeaderTimeout is the default time the trusted source has to send the PROXY protocol header.
+const proxyHeaderTimeout = 5 * time.Second
+
+// proxyV1Prefix and proxyV2Signature start the headers of the PROXY protocol versions.
+var (
+	proxyV1Prefix    = []byte("PROXY ")
+	proxyV2Signature = []byte("\r\n\r\n\x00\r\nQUIT\n")
+)
+
+// proxyV1MaxLength is the maximum length of the PROXY protocol v1 header.
+const proxyV1MaxLength = 107
+
+// ProxyProtocolConfig delivers a set of settings for the PROXY protocol (v1 and v2) support.
+// The connections from TrustedSources (IPs and CIDR prefixes of the load balancers) must start with the header,
+// which replaces the remote address of the connection by the real client one.
+// The connections from other sources are served as is, so their headers aren't trusted.
+// HeaderTimeout bounds the time the header is awaited (5 seconds by default).
+type ProxyProtocolConfig struct {
+	TrustedSources []string      `config:"trusted_sources"`
+	HeaderTimeout  time.Duration `config:"header_timeout"`
+}
+
+// Validate validates ProxyProtocolConfig according to predefined rules.
+func (c ProxyProtocolConfig) Validate() error {
+	var errs []error
+
+	if len(c.TrustedSources) == 0 {
+		errs = append(errs, errors.New("TrustedSources can't be empty"))
+	}
+
+	if _, err := parsePrefixes(c.TrustedSources); err != nil {
+		errs = append(errs, fmt.Errorf("TrustedSources: %w", err))
+	}
+
+	if c.HeaderTimeout < 0 {
+		errs = append(errs, errors.New("HeaderTimeout can't be negative"))
+	}
+	return errors.Join(errs...)
+}
+
+// proxyListener wraps the accepted connections from the trusted sources with the PROXY protocol parsing.
+type proxyListener struct {
+	net.Listener
+	trusted []netip.Prefix
+	timeout time.Duration
+}
+
+// Accept waits for the next connection, the header is parsed later by the connection itself,
+// so that the slow source doesn't block accepting.
+func (l proxyListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	addr, ok := remoteAddr(conn)
+	if !ok || !containsAddr(l.trusted, addr) {
+		return conn, nil
+	}
+
+	return &proxyConn{
+		Conn:    conn,
+		reader:  bufio.NewReader(conn),
+		timeout: l.timeout,
+		once:    new(sync.Once),
+		mutex:   new(sync.Mutex),
+		remote:  new(atomic.Value),
+	}, nil
+}
+
+// newProxyListener - constructor proxyListener.
+func newProxyListener(listener net.Listener, c ProxyProtocolConfig) proxyListener {
+	trusted, _ := parsePrefixes(c.TrustedSources)
+
+	timeout := c.HeaderTimeout
+	if timeout == 0 {
+		timeout = proxyHeaderTimeout
+	}
+
+	return proxyListener{Listener: listener, trusted: trusted, timeout: timeout}
+}
+
+// proxyConn reads the PROXY protocol header on the first use.
+// It remembers the read deadline set by net/http (e.g. of ReadHeaderTimeout), so that it is restored
+// once the header is read.
+type proxyConn struct {
+	net.Conn
+	reader   *bufio.Reader
+	timeout  time.Duration
+	once     *sync.Once
+	mutex    *sync.Mutex
+	deadline time.Time
+	remote   *atomic.Value
+	err      error
+}
+
+// init reads the header within the timeout, or within the read deadline, if it is earlier.
+func (c *proxyConn) init() {
+	c.once.Do(func() {
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+
+		deadline := time.Now().Add(c.timeout)
+		if !c.deadline.IsZero() && c.deadline.Before(deadline) {
+			deadline = c.deadline
+		}
+		c.Conn.SetReadDeadline(deadline)
+		remote, err := readProxyHeader(c.reader)
+		c.Conn.SetReadDeadline(c.deadline)
+
+		c.err = err
+		if remote != nil {
+			c.remote.Store(remote)
+		}
+
+		if c.err != nil {
+			c.err = fmt.Errorf("can't read PROXY protocol header from %s: %w", c.Conn.RemoteAddr(), c.err)
+		}
+	})
+}
+
+// SetDeadline sets the read and write deadlines.
+func (c *proxyConn) SetDeadline(t time.Time) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.deadline = t
+	return c.Conn.SetDeadline(t)
+}
+
+// SetReadDeadline sets the read deadline.
+func (c *proxyConn) SetReadDeadline(t time.Time) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.deadline = t
+	return c.Conn.SetReadDeadline(t)
+}
+
+// Read reads the data following the header.
+func (c *proxyConn) Read(p []byte) (int, error) {
+	c.init()
+	if c.err != nil {
+		return 0, c.err
+	}
+	return c.reader.Read(p)
+}
+
+// RemoteAddr returns the client address passed by the header, or the address of the source,
+// if the header carries none (LOCAL or UNKNOWN) or isn't read yet, so that it never waits for the header.
+func (c *proxyConn) RemoteAddr() net.Addr {
+	if remote, ok := c.remote.Load().(net.Addr); ok {
+		return remote
+	}
+	return c.Conn.RemoteAddr()
+}
+
+// proxyConnKey is the context key of the connection, which may carry the PROXY protocol header.
+type proxyConnKey struct{}
+
+// connContext is the signature of http.Server.ConnContext.
+type connContext = func(ctx context.Context, conn net.Conn) context.Context
+
+// proxyConnContext stores the connection in the context of its requests, before calling next, if it is set.
+func proxyConnContext(next connContext) connContext {
+	return func(ctx context.Context, conn net.Conn) context.Context {
+		ctx = context.WithValue(ctx, proxyConnKey{}, conn)
+		if next != nil {
+			return next(ctx, conn)
+		}
+		return ctx
+	}
+}
+
+// proxyRemoteAddr sets RemoteAddr of the request to the client address passed by the PROXY protocol header.
+// net/http takes the address of the connection before its first read, so the header isn't read by then,
+// while it is, once the request is.
+func proxyRemoteAddr(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if conn, ok := r.Context().Value(proxyConnKey{}).(net.Conn); ok {
+			r.RemoteAddr = conn.RemoteAddr().String()
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// readProxyHeader reads the header of either version, nil address is returned, if it carries none.
+func readProxyHeader(reader *bufio.Reader) (net.Addr, error) {
+	prefix, err := reader.Peek(len(proxyV1Prefix))
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Equal(prefix, proxyV1Prefix) {
+		return readProxyV1(reader)
+	}
+
+	signature, err := reader.Peek(len(proxyV2Signature))
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Equal(signature, proxyV2Signature) {
+		return readProxyV2(reader)
+	}
+	return nil, errors.New("header is missing")
+}
+
+// readProxyV1 reads the human-readable header, e.g. "PROXY TCP4 192.0.2.1 192.0.2.2 56324 443\r\n".
+func readProxyV1(reader *bufio.Reader) (net.Addr, error) {
+	var line []byte
+	for !bytes.HasSuffix(line, []byte("\r\n")) {
+		if len(line) == proxyV1MaxLength {
+			return nil, errors.New("v1 header is too long")
+		}
+		b, err := reader.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		line = append(line, b)
+	}
+
+	fields := strings.Fields(string(line))
+	if len(fields) >= 2 && fields[1] == "UNKNOWN" {
+		return nil, nil
+	}
+	if len(fields) != 6 || (fields[1] != "TCP4" && fields[1] != "TCP6") {
+		return nil, fmt.Errorf("malformed v1 header %q", strings.TrimSpace(string(line)))
+	}
+
+	addr, err := netip.ParseAddr(fields[2])
+	if err != nil {
+		return nil, fmt.Errorf("malformed v1 source address: %w", err)
+	}
+	port, err := strconv.ParseUint(fields[4], 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("malformed v1 source port: %w", err)
+	}
+	return net.TCPAddrFromAddrPort(netip.AddrPortFrom(addr, uint16(port))), nil
+}
+
+// readProxyV2 reads the binary header, the TLVs are skipped.
+func readProxyV2(reader *bufio.Reader) (net.Addr, error) {
+	header := make([]byte, len(proxyV2Signature)+4)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return nil, err
+	}
+
+	versionCommand, family := header[12], header[13]
+	payload := make([]byte, binary.BigEndian.Uint16(header[14:]))
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		return nil, err
+	}
+
+	if versionCommand>>4 != 2 {
+		return nil, fmt.Errorf("unsupported v2 version %d", versionCommand>>4)
+	}
+
+	switch versionCommand & 0x0f {
+	case 0:
+		return nil, nil
+	case 1:
+	default:
+		return nil, fmt.Errorf("unsupported v2 command %d", versionCommand&0x0f)
+	}
+
+	switch family >> 4 {
+	case 1:
+		if len(payload) < 12 {
+			return nil, errors.New("truncated v2 IPv4 addresses")
+		}
+		addr := netip.AddrFrom4([4]byte(payload[0:4]))
+		return net.TCPAddrFromAddrPort(netip.AddrPortFrom(addr, binary.BigEndian.Uint16(payload[8:]))), nil
+	case 2:
+		if len(payload) < 36 {
+			return nil, errors.New("truncated v2 IPv6 addresses")
+		}
+		addr := netip.AddrFrom16([16]byte(payload[0:16]))
+		return net.TCPAddrFromAddrPort(netip.AddrPortFrom(addr, binary.BigEndian.Uint16(payload[32:]))), nil
+	default:
+		return nil, nil
+	}
+}
@@ -0,0 +1,88 @@
+package server
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+)
+
+func TestReadProxyHeader(t *testing.T) {
+	v2 := func(command, family byte, payload ...byte) string {
+		return string(proxyV2Signature) + string([]byte{0x20 | command, family, 0, byte(len(payload))}) +
+			string(payload)
+	}
+
+	tests := []struct {
+		name     string
+		header   string
+		wantAddr string
+		wantErr  bool
+	}{
+		{
+			name:     "v1 TCP4",
+			header:   "PROXY TCP4 192.0.2.1 192.0.2.2 56324 443\r\n",
+			wantAddr: "192.0.2.1:56324",
+		},
+		{
+			name:     "v1 TCP6",
+			header:   "PROXY TCP6 2001:db8::1 2001:db8::2 56324 443\r\n",
+			wantAddr: "[2001:db8::1]:56324",
+		},
+		{
+			name:   "v1 UNKNOWN",
+			header: "PROXY UNKNOWN\r\n",
+		},
+		{
+			name:    "v1 malformed",
+			header:  "PROXY TCP4 192.0.2.1\r\n",
+			wantErr: true,
+		},
+		{
+			name:    "v1 too long",
+			header:  "PROXY " + strings.Repeat("1", proxyV1MaxLength),
+			wantErr: true,
+		},
+		{
+			name:     "v2 TCP4",
+			header:   v2(1, 0x11, 192, 0, 2, 1, 192, 0, 2, 2, 0xdc, 0x04, 0x01, 0xbb),
+			wantAddr: "192.0.2.1:56324",
+		},
+		{
+			name:   "v2 LOCAL",
+			header: v2(0, 0x00),
+		},
+		{
+			name:    "v2 truncated",
+			header:  v2(1, 0x11, 192, 0, 2, 1),
+			wantErr: true,
+		},
+		{
+			name:    "missing",
+			header:  "GET / HTTP/1.1\r\n\r\n",
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			addr, err := readProxyHeader(bufio.NewReader(strings.NewReader(test.header + "GET / HTTP/1.1\r\n")))
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("got %v, want error", addr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("readProxyHeader: %v", err)
+			}
+
+			gotAddr := ""
+			if addr != nil {
+				gotAddr = addr.String()
+			}
+			if gotAddr != test.wantAddr {
+				t.Fatalf("got %q, want %q", gotAddr, test.wantAddr)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package server
+
+import (
+	"errors"
+	"golang.org/x/time/rate"
+	"math"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// rateLimitIdle is the time after which the limiter of the idle client IP is dropped.
+const rateLimitIdle = 3 * time.Minute
+
+// RateLimitConfig delivers a set of settings for the token bucket rate limit of the requests.
+// Rate is the requests per second allowed in total and Burst is the size of the bucket,
+// PerIPRate and PerIPBurst are the same per client IP. Zero Rate or PerIPRate disables the respective limit.
+// The requests exceeding the limit are rejected with 429 and Retry-After.
+type RateLimitConfig struct {
+	Rate       float64 `config:"rate"`
+	Burst      int     `config:"burst"`
+	PerIPRate  float64 `config:"per_ip_rate"`
+	PerIPBurst int     `config:"per_ip_burst"`
+}
+
+// Validate validates RateLimitConfig according to predefined rules.
+func (c RateLimitConfig) Validate() error {
+	var errs []error
+
+	if c.Rate < 0 || c.PerIPRate < 0 {
+		errs = append(errs, errors.New("Rate and PerIPRate can't be negative"))
+	}
+
+	if c.Rate == 0 && c.PerIPRate == 0 {
+		errs = append(errs, errors.New("at least one of Rate and PerIPRate must be set"))
+	}
+
+	if c.Rate > 0 && c.Burst <= 0 {
+		errs = append(errs, errors.New("Burst must be positive, when Rate is set"))
+	}
+
+	if c.PerIPRate > 0 && c.PerIPBurst <= 0 {
+		errs = append(errs, errors.New("PerIPBurst must be positive, when PerIPRate is set"))
+	}
+	return errors.Join(errs...)
+}
+
+// clientLimiter is the limiter of the client IP with the time it was used last.
+type clientLimiter struct {
+	limiter *rate.Limiter
+	seen    time.Time
+}
+
+// rateLimiter predetermines the consistency of the rate limit of the requests.
+type rateLimiter struct {
+	config  RateLimitConfig
+	global  *rate.Limiter
+	mutex   *sync.Mutex
+	clients map[netip.Addr]*clientLimiter
+	swept   time.Time
+}
+
+// client returns the limiter of the client IP, the limiters of the idle clients are dropped on the way.
+func (l *rateLimiter) client(addr netip.Addr) *rate.Limiter {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := time.Now()
+	if now.Sub(l.swept) > rateLimitIdle {
+		for addr, client := range l.clients {
+			if now.Sub(client.seen) > rateLimitIdle {
+				delete(l.clients, addr)
+			}
+		}
+		l.swept = now
+	}
+
+	client, ok := l.clients[addr]
+	if !ok {
+		client = &clientLimiter{limiter: rate.NewLimiter(rate.Limit(l.config.PerIPRate), l.config.PerIPBurst)}
+		l.clients[addr] = client
+	}
+	client.seen = now
+	return client.limiter
+}
+
+// reserve takes the token of the request, the delay until the token is available is returned otherwise.
+func reserve(limiter *rate.Limiter) (time.Duration, bool) {
+	reservation := limiter.Reserve()
+	if delay := reservation.Delay(); delay > 0 {
+		reservation.Cancel()
+		return delay, false
+	}
+	return 0, true
+}
+
+// allow reports whether the request is within the limits, the delay until it would be is returned otherwise.
+// The limit of the client IP is checked first, so that the abusive client doesn't drain the global bucket.
+func (l *rateLimiter) allow(r *http.Request) (time.Duration, bool) {
+	if l.config.PerIPRate > 0 {
+		if addr, ok := requestAddr(r); ok {
+			if delay, ok := reserve(l.client(addr)); !ok {
+				return delay, false
+			}
+		}
+	}
+
+	if l.global != nil {
+		return reserve(l.global)
+	}
+	return 0, true
+}
+
+// wrap wraps the handler rejecting the requests exceeding the limit.
+func (l *rateLimiter) wrap(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if delay, ok := l.allow(r); !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(delay.Seconds()))))
+			http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// newRateLimiter - constructor rateLimiter.
+func newRateLimiter(c RateLimitConfig) *rateLimiter {
+	limiter := &rateLimiter{
+		config:  c,
+		mutex:   new(sync.Mutex),
+		clients: make(map[netip.Addr]*clientLimiter),
+		swept:   time.Now(),
+	}
+	if c.Rate > 0 {
+		limiter.global = rate.NewLimiter(rate.Limit(c.Rate), c.Burst)
+	}
+	return limiter
+}
@@ -0,0 +1,100 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// clientIPKey is the context key of the client IP.
+type clientIPKey struct{}
+
+// ClientIPFromContext returns the client IP of the request resolved by the server: the address forwarded
+// by the trusted proxies (see Config.TrustedProxies) or the address of the peer.
+// False is returned, if the server hasn't resolved it.
+func ClientIPFromContext(ctx context.Context) (netip.Addr, bool) {
+	addr, ok := ctx.Value(clientIPKey{}).(netip.Addr)
+	return addr, ok
+}
+
+// realIP wraps the handler resolving the client IP of each request. The forwarding headers (Forwarded,
+// otherwise X-Forwarded-For) are taken into account only when the peer is trusted: the chain is walked
+// from the nearest hop and the first untrusted address is the client.
+func realIP(trusted []netip.Prefix, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		addr, ok := peerAddr(r)
+		if !ok {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		if containsAddr(trusted, addr) {
+			hops := forwardedFor(r.Header)
+			for i := len(hops) - 1; i >= 0; i-- {
+				addr = hops[i]
+				if !containsAddr(trusted, addr) {
+					break
+				}
+			}
+		}
+
+		handler.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), clientIPKey{}, addr)))
+	})
+}
+
+// peerAddr returns IP of the peer of the request.
+func peerAddr(r *http.Request) (netip.Addr, bool) {
+	addrPort, err := netip.ParseAddrPort(r.RemoteAddr)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addrPort.Addr().Unmap(), true
+}
+
+// requestAddr returns the client IP of the request resolved by the server, or IP of the peer.
+func requestAddr(r *http.Request) (netip.Addr, bool) {
+	if addr, ok := ClientIPFromContext(r.Context()); ok {
+		return addr, true
+	}
+	return peerAddr(r)
+}
+
+// forwardedFor returns the chain of the forwarded addresses from the farthest to the nearest hop,
+// the malformed and the obfuscated ones are skipped.
+func forwardedFor(header http.Header) []netip.Addr {
+	var values []string
+	if forwarded := header.Values("Forwarded"); len(forwarded) != 0 {
+		for _, element := range strings.Split(strings.Join(forwarded, ","), ",") {
+			for _, pair := range strings.Split(element, ";") {
+				key, value, _ := strings.Cut(strings.TrimSpace(pair), "=")
+				if strings.EqualFold(key, "for") {
+					values = append(values, strings.Trim(value, `"`))
+				}
+			}
+		}
+	} else {
+		values = strings.Split(strings.Join(header.Values("X-Forwarded-For"), ","), ",")
+	}
+
+	addrs := make([]netip.Addr, 0, len(values))
+	for _, value := range values {
+		if addr, ok := parseHop(strings.TrimSpace(value)); ok {
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs
+}
+
+// parseHop parses the forwarded address, which is IP optionally with port, IPv6 may be bracketed.
+func parseHop(value string) (netip.Addr, bool) {
+	if addrPort, err := netip.ParseAddrPort(value); err == nil {
+		return addrPort.Addr().Unmap(), true
+	}
+
+	addr, err := netip.ParseAddr(strings.TrimSuffix(strings.TrimPrefix(value, "["), "]"))
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addr.Unmap(), true
+}
@@ -0,0 +1,37 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+)
+
+// recoverPanics wraps the handler recovering the panics: the response becomes 500, unless it is already
+// started, and the panic is reported by the logger and OnError hook.
+// http.ErrAbortHandler is re-panicked, so that the connection is aborted as intended.
+func (s *Server) recoverPanics(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := &recorder{ResponseWriter: w}
+
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			if v == http.ErrAbortHandler {
+				panic(v)
+			}
+
+			stack := debug.Stack()
+			s.log().Error("panic recovered", "method", r.Method, "path", r.URL.Path, "panic", v,
+				"request_id", RequestIDFromContext(r.Context()), "stack", string(stack))
+			s.hooks.error(fmt.Errorf("panic serving %s %s: %v\n%s", r.Method, r.URL.Path, v, stack))
+
+			if rec.status == 0 {
+				http.Error(rec, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}
+		}()
+
+		handler.ServeHTTP(rec, r)
+	})
+}
@@ -0,0 +1,121 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golang-mixins/servers"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// log returns the current logger of the server.
+func (s *Server) log() servers.Logger {
+	return *s.logger.Load()
+}
+
+// serverLogger forwards to the current logger of the server, so that the long-lived consumers
+// (http.Server.ErrorLog, certificates provider) follow the logger replaced by Reload.
+type serverLogger struct {
+	server *Server
+}
+
+// Printf logs the unstructured message.
+func (l serverLogger) Printf(format string, v ...interface{}) {
+	l.server.log().Printf(format, v...)
+}
+
+// Info logs the lifecycle event.
+func (l serverLogger) Info(msg string, keysAndValues ...interface{}) {
+	l.server.log().Info(msg, keysAndValues...)
+}
+
+// Error logs the failure.
+func (l serverLogger) Error(msg string, keysAndValues ...interface{}) {
+	l.server.log().Error(msg, keysAndValues...)
+}
+
+// Reload applies the reloadable settings of the config to the running server without dropping the connections:
+// Logger, StopTimeout, DrainTimeout, PreStopDelay and the certificates of TLS (CertFile and KeyFile, Certificates
+// or Provider).
+// net/http reads its timeouts unsynchronized, so the changes of ReadTimeout, ReadHeaderTimeout, WriteTimeout,
+// IdleTimeout and MaxHeaderBytes are refused, they require restart. The rest of the settings are ignored.
+// Nothing is applied, if the config is refused.
+func (s *Server) Reload(cfg Config) error {
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("can't reload: %w", err)
+	}
+
+	var errs []error
+	for _, field := range []struct {
+		name    string
+		changed bool
+	}{
+		{"ReadTimeout", cfg.ReadTimeout != s.http.ReadTimeout},
+		{"ReadHeaderTimeout", cfg.ReadHeaderTimeout != s.http.ReadHeaderTimeout},
+		{"WriteTimeout", cfg.WriteTimeout != s.http.WriteTimeout},
+		{"IdleTimeout", cfg.IdleTimeout != s.http.IdleTimeout},
+		{"MaxHeaderBytes", cfg.MaxHeaderBytes != s.http.MaxHeaderBytes},
+		{"TLS", (cfg.TLS == nil) != (s.http.TLSConfig == nil)},
+	} {
+		if field.changed {
+			errs = append(errs, fmt.Errorf("%s can't be reloaded", field.name))
+		}
+	}
+
+	if cfg.TLS != nil && (cfg.TLS.Autocert != nil || s.certificates.Load() == nil) {
+		errs = append(errs, errors.New("TLS can be reloaded only from CertFile and KeyFile, Certificates or Provider"))
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("can't reload: %w", err)
+	}
+
+	if cfg.TLS != nil {
+		source, err := s.newCertificates(*cfg.TLS)
+		if err != nil {
+			return fmt.Errorf("can't reload: %w", err)
+		}
+
+		if previous := s.certificates.Swap(source); previous.closer != nil {
+			if err = previous.closer.Close(); err != nil {
+				s.log().Error("release error", "error", err)
+			}
+		}
+	}
+
+	s.mutex.Lock()
+	s.stopTimeout = cfg.StopTimeout
+	s.drainTimeout = cfg.DrainTimeout
+	s.preStop = cfg.PreStopDelay
+	s.mutex.Unlock()
+
+	s.logger.Store(&cfg.Logger)
+	s.log().Info("configuration reloaded")
+
+	return nil
+}
+
+// ReloadOnHangup reloads the server with the config returned by load on each SIGHUP, until ctx is done.
+// The failures are logged, the server keeps the previous settings then.
+func (s *Server) ReloadOnHangup(ctx context.Context, load func() (Config, error)) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	defer signal.Stop(signals)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-signals:
+			cfg, err := load()
+			if err == nil {
+				err = s.Reload(cfg)
+			}
+			if err != nil {
+				s.log().Error("reload error", "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+// maxRequestIDLength bounds the length of the incoming request ID, the longer ones are replaced.
+const maxRequestIDLength = 128
+
+// RequestIDConfig delivers a set of settings for the request ID assignment.
+// The ID is taken from Header of the request (X-Request-ID by default), or generated by Generate
+// (random 128 bits in hex by default), it is stored in the request context and echoed in the response header.
+type RequestIDConfig struct {
+	Header   string `config:"header"`
+	Generate func() string
+}
+
+// withDefaults returns RequestIDConfig with the empty settings set to the defaults.
+func (c RequestIDConfig) withDefaults() RequestIDConfig {
+	if c.Header == "" {
+		c.Header = "X-Request-ID"
+	}
+	if c.Generate == nil {
+		c.Generate = generateRequestID
+	}
+	return c
+}
+
+// requestIDKey is the context key of the request ID.
+type requestIDKey struct{}
+
+// RequestIDFromContext returns the request ID assigned by the server, or empty string, if there is none.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// generateRequestID returns random 128 bits in hex.
+func generateRequestID() string {
+	var id [16]byte
+	rand.Read(id[:])
+	return hex.EncodeToString(id[:])
+}
+
+// requestID wraps the handler assigning the request ID to each request.
+func requestID(c RequestIDConfig, handler http.Handler) http.Handler {
+	c = c.withDefaults()
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(c.Header)
+		if id == "" || len(id) > maxRequestIDLength {
+			id = c.Generate()
+		}
+
+		w.Header().Set(c.Header, id)
+		handler.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestIDKey{}, id)))
+	})
+}
@@ -0,0 +1,95 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// SecurityHeadersConfig delivers a set of the security headers added to each response, the empty ones aren't added.
+// Strict-Transport-Security is added to the responses served over TLS only, as required by RFC 6797,
+// once HSTSMaxAge is positive. The handler may override any of the headers by setting it itself.
+type SecurityHeadersConfig struct {
+	HSTSMaxAge            time.Duration `config:"hsts_max_age"`
+	HSTSIncludeSubdomains bool          `config:"hsts_include_subdomains"`
+	HSTSPreload           bool          `config:"hsts_preload"`
+	ContentTypeOptions    string        `config:"content_type_options"`
+	FrameOptions          string        `config:"frame_options"`
+	ReferrerPolicy        string        `config:"referrer_policy"`
+	ContentSecurityPolicy string        `config:"content_security_policy"`
+}
+
+// DefaultSecurityHeaders returns SecurityHeadersConfig with the baseline headers:
+// HSTS for 2 years including subdomains, nosniff, DENY framing and strict-origin-when-cross-origin referrer.
+func DefaultSecurityHeaders() *SecurityHeadersConfig {
+	return &SecurityHeadersConfig{
+		HSTSMaxAge:            2 * 365 * 24 * time.Hour,
+		HSTSIncludeSubdomains: true,
+		ContentTypeOptions:    "nosniff",
+		FrameOptions:          "DENY",
+		ReferrerPolicy:        "strict-origin-when-cross-origin",
+	}
+}
+
+// Validate validates SecurityHeadersConfig according to predefined rules.
+func (c SecurityHeadersConfig) Validate() error {
+	var errs []error
+
+	if c.HSTSMaxAge < 0 {
+		errs = append(errs, errors.New("HSTSMaxAge can't be negative"))
+	}
+
+	if (c.HSTSIncludeSubdomains || c.HSTSPreload) && c.HSTSMaxAge == 0 {
+		errs = append(errs, errors.New("HSTSIncludeSubdomains and HSTSPreload can be set only together with HSTSMaxAge"))
+	}
+
+	if c.HSTSPreload && !c.HSTSIncludeSubdomains {
+		errs = append(errs, errors.New("HSTSPreload requires HSTSIncludeSubdomains"))
+	}
+	return errors.Join(errs...)
+}
+
+// hsts returns the value of Strict-Transport-Security, or empty string, if it is disabled.
+func (c SecurityHeadersConfig) hsts() string {
+	if c.HSTSMaxAge <= 0 {
+		return ""
+	}
+
+	value := "max-age=" + strconv.FormatInt(int64(c.HSTSMaxAge/time.Second), 10)
+	if c.HSTSIncludeSubdomains {
+		value += "; includeSubDomains"
+	}
+	if c.HSTSPreload {
+		value += "; preload"
+	}
+	return value
+}
+
+// securityHeaders wraps the handler adding the security headers to each response.
+func securityHeaders(c SecurityHeadersConfig, handler http.Handler) http.Handler {
+	hsts := c.hsts()
+	headers := make(map[string]string, 4)
+	for name, value := range map[string]string{
+		"X-Content-Type-Options":  c.ContentTypeOptions,
+		"X-Frame-Options":         c.FrameOptions,
+		"Referrer-Policy":         c.ReferrerPolicy,
+		"Content-Security-Policy": c.ContentSecurityPolicy,
+	} {
+		if value != "" {
+			headers[name] = value
+		}
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := w.Header()
+		for name, value := range headers {
+			header.Set(name, value)
+		}
+		if hsts != "" && r.TLS != nil {
+			header.Set("Strict-Transport-Security", hsts)
+		}
+
+		handler.ServeHTTP(w, r)
+	})
+}
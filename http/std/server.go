@@ -3,111 +3,623 @@ package server
 
 import (
 	"context"
-	"go.opencensus.io/trace"
-	"golang.org/x/xerrors"
-	"io"
-	Log "log"
+	"errors"
+	"fmt"
+	"github.com/golang-mixins/servers"
+	"github.com/golang-mixins/servers/certs"
+	"github.com/golang-mixins/servers/systemd"
+	"go.opentelemetry.io/otel/trace"
+	"net"
 	"net/http"
-	"regexp"
+	"slices"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"syscall"
 	"time"
 )
 
 // Config delivers a set of settings for server implementation.
+// Addr is either "host:port" (port 0 binds an ephemeral port, see servers.ValidateAddr)
+// or a path of the unix domain socket prefixed with "unix://".
+// Listener, if set, is served instead of binding Addr.
+// SocketActivation serves the first listener passed by systemd socket activation instead of binding Addr.
+// ReusePortListeners, if positive, binds that many listeners with SO_REUSEPORT on Addr and serves them concurrently.
+// SystemdNotify notifies systemd once the listener is bound and once the server is stopping,
+// and notifies systemd watchdog, if it is enabled for the service.
+// Hooks are invoked on the lifecycle events of the server.
+// PreStopDelay delays the shutdown started by Stop, while the server keeps serving as usual, so that load balancers
+// have time to remove the server from rotation. The delay isn't counted in StopTimeout.
+// Health, if set, serves the liveness and readiness endpoints reflecting the state of the server.
+// TracerProvider provides the tracer of the startup and shutdown spans, the global OpenTelemetry provider is used,
+// if it is nil.
+// AccessLog, if set, logs each request (method, path, status, bytes, latency, remote addr) once it is served,
+// e.g. servers.NewJSONLogger(os.Stdout).
+// RecoverPanics recovers the panics of the handler, responds 500 and reports them to Hooks.OnError.
+// RequestID, if set, assigns the request ID to each request, which is included in the access and panic logs.
+// MaxConcurrentRequests, if positive, bounds the requests served by Router at once, the excess is rejected
+// with 503 and Retry-After.
+// ConnLimit, if set, bounds the simultaneous connections per client IP.
+// RateLimit, if set, bounds the rate of the requests served by Router in total and per client IP.
+// LoadShedding, if set, rejects the fraction of the requests to Router under overload.
+// MaxRequestBodyBytes, if positive, bounds the request body read by Router, reading beyond it fails
+// with *http.MaxBytesError and the connection is closed after the response.
+// HandlerTimeout, if positive, bounds the time Router may take: its context is canceled and the client receives
+// 503 once it elapses. Router can't flush or hijack the connection then, so streaming handlers aren't supported.
+// ProxyProtocol, if set, takes the remote address from the PROXY protocol header sent by the trusted sources.
+// ConnLimit counts the connections of the sources, not of the clients behind them.
+// TrustedProxies holds the IPs and the CIDR prefixes of the proxies, whose forwarding headers are trusted
+// to resolve the client IP (see ClientIPFromContext), which is used by the access log and RateLimit.
+// IPFilter, if set, closes the connections of the peers, which aren't allowed, once they are accepted.
+// Like ConnLimit, it sees the PROXY protocol sources rather than the clients behind them.
+// ListenControl, if set, is called on the socket before it is bound, to set the platform specific options.
+// TCPKeepAlive is the keep-alive period of the accepted connections (15 seconds by default, negative disables).
+// SO_REUSEADDR is enabled by Go on unix, the backlog is taken from the system (net.core.somaxconn on linux).
+// BindRetry, if set, retries the bind failed with EADDRINUSE instead of failing Listen at once.
+// Addrs, if set instead of Addr, binds each of the addresses (in the format of Addr), all of them are served
+// by the same handler and drained together by Stop.
+// MaintenanceRetryAfter is Retry-After of the responses in maintenance mode (1 minute by default),
+// see EnterMaintenance.
+// SecurityHeaders, if set, adds the security headers to each response, including the health endpoints
+// and the rejections, e.g. DefaultSecurityHeaders().
+// HijackedConnections is the policy of Stop towards the hijacked connections, see HijackedPolicy.
+// ConnState, if set, is called on each change of the state of the connections, after the server tracked it,
+// see http.Server.ConnState.
+// BaseContext and ConnContext, if set, provide the base context of the requests per listener and per connection,
+// e.g. to pass the per-server values to the handlers, see http.Server.BaseContext and http.Server.ConnContext.
+// ErrorLogFilter, if set, drops or rate limits the noise (e.g. TLS handshake errors of the scanners)
+// logged by net/http to Logger.
+// StopProgressInterval, if positive, logs the connections and requests remaining every interval, while Stop drains,
+// see Hooks.OnDrainProgress.
+// DrainTimeout, if positive, bounds the drain within StopTimeout, the connections remaining after it are closed
+// forcibly and reported by *ForcedCloseError and Hooks.OnForceClose, so that the rest of StopTimeout is left
+// for closing and the companion servers.
 type Config struct {
-	Addr              string
-	ReadTimeout       time.Duration
-	ReadHeaderTimeout time.Duration
-	WriteTimeout      time.Duration
-	IdleTimeout       time.Duration
-	StopTimeout       time.Duration
-	MaxHeaderBytes    int
-	ErrorsOutput      io.Writer
-	Router            http.Handler
-	KeepAliveEnabled  bool
+	Addr                  string        `config:"addr"`
+	ReadTimeout           time.Duration `config:"read_timeout"`
+	ReadHeaderTimeout     time.Duration `config:"read_header_timeout"`
+	WriteTimeout          time.Duration `config:"write_timeout"`
+	IdleTimeout           time.Duration `config:"idle_timeout"`
+	StopTimeout           time.Duration `config:"stop_timeout"`
+	PreStopDelay          time.Duration `config:"pre_stop_delay"`
+	MaxHeaderBytes        int           `config:"max_header_bytes"`
+	Logger                servers.Logger
+	Router                http.Handler
+	KeepAliveEnabled      bool         `config:"keep_alive"`
+	TLS                   *TLSConfig   `config:"tls"`
+	HTTP2                 *HTTP2Config `config:"http2"`
+	Listener              net.Listener
+	SocketActivation      bool `config:"socket_activation"`
+	SystemdNotify         bool `config:"systemd_notify"`
+	ReusePortListeners    int  `config:"reuse_port_listeners"`
+	Hooks                 Hooks
+	Health                *HealthConfig `config:"health"`
+	TracerProvider        trace.TracerProvider
+	AccessLog             servers.Logger
+	RecoverPanics         bool                 `config:"recover_panics"`
+	RequestID             *RequestIDConfig     `config:"request_id"`
+	MaxConcurrentRequests int                  `config:"max_concurrent_requests"`
+	ConnLimit             *ConnLimitConfig     `config:"conn_limit"`
+	RateLimit             *RateLimitConfig     `config:"rate_limit"`
+	LoadShedding          *LoadSheddingConfig  `config:"load_shedding"`
+	MaxRequestBodyBytes   int64                `config:"max_request_body_bytes"`
+	HandlerTimeout        time.Duration        `config:"handler_timeout"`
+	ProxyProtocol         *ProxyProtocolConfig `config:"proxy_protocol"`
+	TrustedProxies        []string             `config:"trusted_proxies"`
+	IPFilter              *IPFilterConfig      `config:"ip_filter"`
+	ListenControl         func(network, address string, conn syscall.RawConn) error
+	TCPKeepAlive          time.Duration          `config:"tcp_keep_alive"`
+	BindRetry             *BindRetryConfig       `config:"bind_retry"`
+	Addrs                 []string               `config:"addrs"`
+	MaintenanceRetryAfter time.Duration          `config:"maintenance_retry_after"`
+	SecurityHeaders       *SecurityHeadersConfig `config:"security_headers"`
+	HijackedConnections   HijackedPolicy         `config:"hijacked_connections"`
+	ConnState             func(conn net.Conn, state http.ConnState)
+	BaseContext           func(listener net.Listener) context.Context
+	ConnContext           func(ctx context.Context, conn net.Conn) context.Context
+	ErrorLogFilter        *ErrorLogFilterConfig `config:"error_log_filter"`
+	StopProgressInterval  time.Duration         `config:"stop_progress_interval"`
+	DrainTimeout          time.Duration         `config:"drain_timeout"`
 }
 
 // Validate validates Config according to predefined rules.
 func (c Config) Validate() error {
+	var errs []error
+
 	if c.Router == nil {
-		return xerrors.New("Router can't be nil")
+		errs = append(errs, errors.New("Router can't be nil"))
 	}
 
-	if c.StopTimeout == 0 {
-		return xerrors.New("StopTimeout can't be empty")
+	if c.StopTimeout < 0 {
+		errs = append(errs, errors.New("StopTimeout can't be negative"))
 	}
 
-	addrRegExp := regexp.MustCompile(`^:[0-9]+$`)
-	if ok := addrRegExp.MatchString(c.Addr); !ok {
-		return xerrors.New("RegExp: Addr must be in a valid format")
+	if c.DrainTimeout < 0 {
+		errs = append(errs, errors.New("DrainTimeout can't be negative"))
 	}
 
-	if c.ErrorsOutput == nil {
-		return xerrors.New("ErrorsOutput can't be nil")
+	if c.StopTimeout != 0 && c.DrainTimeout > c.StopTimeout {
+		errs = append(errs, errors.New("DrainTimeout can't exceed StopTimeout"))
 	}
-	return nil
+
+	if c.PreStopDelay < 0 {
+		errs = append(errs, errors.New("PreStopDelay can't be negative"))
+	}
+
+	switch {
+	case c.Listener != nil && c.SocketActivation:
+		errs = append(errs, errors.New("Listener can't be set together with SocketActivation"))
+	case c.Listener != nil:
+		if c.Addr != "" || len(c.Addrs) != 0 {
+			errs = append(errs, errors.New("Addr and Addrs can't be set together with Listener"))
+		}
+	case c.SocketActivation:
+		if c.Addr != "" || len(c.Addrs) != 0 {
+			errs = append(errs, errors.New("Addr and Addrs can't be set together with SocketActivation"))
+		}
+	case len(c.Addrs) != 0:
+		if c.Addr != "" {
+			errs = append(errs, errors.New("Addr can't be set together with Addrs"))
+		}
+
+		for _, addr := range c.Addrs {
+			if err := validateAddr(addr); err != nil {
+				errs = append(errs, fmt.Errorf("Addrs: %w", err))
+			}
+		}
+	default:
+		if err := validateAddr(c.Addr); err != nil {
+			errs = append(errs, fmt.Errorf("Addr: %w", err))
+		}
+	}
+
+	if c.Logger == nil {
+		errs = append(errs, errors.New("Logger can't be nil"))
+	}
+
+	if c.HijackedConnections < HijackedIgnore || c.HijackedConnections > HijackedClose {
+		errs = append(errs, errors.New("unknown HijackedConnections policy"))
+	}
+
+	if c.MaxConcurrentRequests < 0 {
+		errs = append(errs, errors.New("MaxConcurrentRequests can't be negative"))
+	}
+
+	if c.StopProgressInterval < 0 {
+		errs = append(errs, errors.New("StopProgressInterval can't be negative"))
+	}
+
+	if c.MaintenanceRetryAfter < 0 {
+		errs = append(errs, errors.New("MaintenanceRetryAfter can't be negative"))
+	}
+
+	if c.HandlerTimeout < 0 {
+		errs = append(errs, errors.New("HandlerTimeout can't be negative"))
+	}
+
+	if c.MaxRequestBodyBytes < 0 {
+		errs = append(errs, errors.New("MaxRequestBodyBytes can't be negative"))
+	}
+
+	if c.ReusePortListeners < 0 {
+		errs = append(errs, errors.New("ReusePortListeners can't be negative"))
+	}
+
+	if c.ReusePortListeners > 0 && (c.Listener != nil || c.SocketActivation || len(c.Addrs) != 0 ||
+		strings.HasPrefix(c.Addr, unixScheme)) {
+		errs = append(errs, errors.New("ReusePortListeners can be set only together with tcp Addr"))
+	}
+
+	if c.TLS != nil {
+		if err := c.TLS.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("TLS: %w", err))
+		}
+	}
+
+	if c.Health != nil {
+		if err := c.Health.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("Health: %w", err))
+		}
+	}
+
+	if c.ConnLimit != nil {
+		if err := c.ConnLimit.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("ConnLimit: %w", err))
+		}
+	}
+
+	if c.RateLimit != nil {
+		if err := c.RateLimit.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("RateLimit: %w", err))
+		}
+	}
+
+	if c.LoadShedding != nil {
+		if err := c.LoadShedding.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("LoadShedding: %w", err))
+		}
+	}
+
+	if c.BindRetry != nil {
+		if err := c.BindRetry.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("BindRetry: %w", err))
+		}
+	}
+
+	if c.IPFilter != nil {
+		if err := c.IPFilter.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("IPFilter: %w", err))
+		}
+	}
+
+	if c.ProxyProtocol != nil {
+		if err := c.ProxyProtocol.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("ProxyProtocol: %w", err))
+		}
+	}
+
+	if _, err := parsePrefixes(c.TrustedProxies); err != nil {
+		errs = append(errs, fmt.Errorf("TrustedProxies: %w", err))
+	}
+
+	if c.SecurityHeaders != nil {
+		if err := c.SecurityHeaders.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("SecurityHeaders: %w", err))
+		}
+	}
+
+	if c.ErrorLogFilter != nil {
+		if err := c.ErrorLogFilter.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("ErrorLogFilter: %w", err))
+		}
+	}
+
+	if c.HTTP2 != nil {
+		if c.TLS == nil {
+			errs = append(errs, errors.New("HTTP2 can be set only together with TLS"))
+		} else if len(c.TLS.NextProtos) != 0 && !slices.Contains(c.TLS.NextProtos, "h2") {
+			errs = append(errs, errors.New("HTTP2 can't be set, unless TLS.NextProtos contain h2"))
+		}
+
+		if err := c.HTTP2.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("HTTP2: %w", err))
+		}
+	}
+	return errors.Join(errs...)
 }
 
 // Server predetermines the consistency of the implementation servers.Launcher.
 // Using the methods of the structure, without being initialized by the New() constructor, will lead to panic.
 type Server struct {
-	stopTimeout time.Duration
-	mutex       *sync.RWMutex
-	shutdown    bool
-	http        *http.Server
+	stopTimeout   time.Duration
+	drainTimeout  time.Duration
+	progress      time.Duration
+	preStop       time.Duration
+	mutex         *sync.RWMutex
+	bindMutex     *sync.Mutex
+	shutdown      bool
+	stopping      chan struct{}
+	http          *http.Server
+	listener      net.Listener
+	listeners     []net.Listener
+	reusePort     int
+	companions    []*companion
+	certificates  atomic.Pointer[certificates]
+	tickets       *sessionTickets
+	clientCAs     *certs.CAFile
+	maintenance   atomic.Bool
+	watchdog      chan struct{}
+	ready         chan struct{}
+	stateMutex    *sync.RWMutex
+	state         State
+	done          chan struct{}
+	exitOnce      *sync.Once
+	err           error
+	logger        atomic.Pointer[servers.Logger]
+	hooks         Hooks
+	tracker       *tracker
+	hijacked      HijackedPolicy
+	connLimiter   *connLimiter
+	shedder       *shedder
+	proxyProtocol *ProxyProtocolConfig
+	ipFilter      *IPFilterConfig
+	listenConfig  net.ListenConfig
+	bindRetry     *BindRetryConfig
+	addrs         []string
+	tracer        trace.Tracer
+}
+
+// Listen binds the listeners of the server, so that bind errors are returned synchronously.
+// The companion servers (Health.Addr and Autocert.ChallengeAddr) are bound by Listen as well.
+// Serve calls Listen implicitly, if the server isn't listening yet.
+// The retries of the bind (see Config.BindRetry) are interrupted by Stop.
+func (s *Server) Listen() error {
+	s.bindMutex.Lock()
+	defer s.bindMutex.Unlock()
+
+	s.mutex.RLock()
+	listening := s.listeners != nil
+	s.mutex.RUnlock()
+	if listening {
+		return nil
+	}
+
+	if err := s.transit(StateListening); err != nil {
+		err = fmt.Errorf("can't listen: %w", err)
+		s.log().Error("error Listen", "error", err)
+		return err
+	}
+
+	_, span := s.tracer.Start(context.Background(), "http server listen")
+	listeners, err := s.listenRetrying()
+	servers.EndSpan(span, err)
+	if errors.Is(err, servers.ErrServerClosed) {
+		err = fmt.Errorf("can't listen: %w", err)
+		s.log().Error("error Listen", "error", err)
+		return err
+	}
+	if err != nil {
+		err = &servers.BindError{Addr: strings.Join(s.addrs, ", "), Err: err}
+		s.transit(StateFailed)
+		s.log().Error("error Listen", "error", err)
+		return err
+	}
+
+	// The companions (e.g. the health port) are bound with the main listeners, so that the server isn't ready
+	// while any of them can't serve.
+	if err = s.listenCompanions(); err != nil {
+		for _, listener := range listeners {
+			listener.Close()
+		}
+		s.transit(StateFailed)
+		s.log().Error("error Listen", "error", err)
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.shutdown {
+		for _, listener := range listeners {
+			listener.Close()
+		}
+		s.closeCompanions(s.companions)
+		err = fmt.Errorf("can't listen: %w", servers.ErrServerClosed)
+		s.log().Error("error Listen", "error", err)
+		return err
+	}
+	s.listeners = s.wrapListeners(listeners)
+	for _, listener := range listeners {
+		s.log().Info("listening", "addr", listener.Addr())
+	}
+
+	close(s.ready)
+	s.notifyReady()
+
+	return nil
 }
 
 // Serve serving the server.
+// Nil is returned, once the server is closed by Stop.
 func (s *Server) Serve() error {
-	err := s.http.ListenAndServe()
+	if err := s.Listen(); err != nil {
+		s.hooks.error(err)
+		s.exit(err)
+		return err
+	}
+
+	if err := s.transit(StateServing); err != nil {
+		err = fmt.Errorf("can't serve: %w", err)
+		s.log().Error("error Serve", "error", err)
+		return err
+	}
+
+	s.hooks.start(s.Addr())
+
+	s.serveCompanions()
+
+	s.mutex.RLock()
+	listeners := s.listeners
+	s.mutex.RUnlock()
+
+	serving := make(chan error, len(listeners))
+	for _, listener := range listeners {
+		go func(listener net.Listener) {
+			serving <- s.serve(listener)
+		}(listener)
+	}
+
+	err := <-serving
+	if !errors.Is(err, http.ErrServerClosed) {
+		// The rest of the listeners are closed, so that the failed server doesn't keep accepting on them.
+		for _, listener := range listeners {
+			listener.Close()
+		}
+	}
+	for i := 1; i < len(listeners); i++ {
+		<-serving
+	}
+
+	if errors.Is(err, http.ErrServerClosed) {
+		s.log().Info("exit Serve, server closed")
+		return nil
+	}
 	if err != nil {
-		err = xerrors.New(err.Error())
-		s.http.ErrorLog.Printf("error ListenAndServe: %s", err.Error())
+		err = fmt.Errorf("error serving: %w", err)
+		s.log().Error("error Serve", "error", err)
 	} else {
-		s.http.ErrorLog.Println("unexpected exit ListenAndServe")
+		s.log().Error("unexpected exit Serve")
 	}
 
+	s.transit(StateFailed)
+	s.hooks.error(err)
+	s.exit(err)
 	return err
 }
 
+// Ready returns the channel, which is closed once the server is listening and accepting connections.
+func (s *Server) Ready() <-chan struct{} {
+	return s.ready
+}
+
+// Addr returns the address the server is listening on, or nil, if it isn't listening yet.
+// It reports the actual port, when Addr is configured as ":0".
+func (s *Server) Addr() net.Addr {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if len(s.listeners) == 0 {
+		return nil
+	}
+	return s.listeners[0].Addr()
+}
+
+// Drain switches the server into lame-duck mode: the state becomes StateDraining, so the readiness fails,
+// and keep-alives are disabled, so the clients reconnect elsewhere, while the requests are still served.
+// Stop is still required to stop the server.
+func (s *Server) Drain() error {
+	if err := s.transit(StateDraining); err != nil {
+		err = fmt.Errorf("can't drain: %w", err)
+		s.log().Error("error Drain", "error", err)
+		return err
+	}
+
+	s.log().Info("draining http server")
+	s.http.SetKeepAlivesEnabled(false)
+
+	return nil
+}
+
+// RegisterOnShutdown registers the function to call once Stop starts the shutdown of the underlying http.Server,
+// e.g. to notify long-lived connections (SSE, WebSockets) to close.
+func (s *Server) RegisterOnShutdown(f func()) {
+	s.http.RegisterOnShutdown(f)
+}
+
+// serve runs the accept loop on the listener.
+func (s *Server) serve(listener net.Listener) error {
+	if s.http.TLSConfig != nil {
+		return s.http.ServeTLS(listener, "", "")
+	}
+	return s.http.Serve(listener)
+}
+
 // Stop stops the server.
-func (s *Server) Stop(ctx context.Context) error {
-	_, span := trace.StartSpan(ctx, "http server stop")
-	defer span.End()
+// The server is shut down gracefully within DrainTimeout (StopTimeout, if it isn't set) or until ctx is done,
+// whichever is earlier, after that it is closed and *ForcedCloseError reporting the stragglers is returned.
+// The forced close has the rest of StopTimeout and ctx, but at least servers.ForceCloseTimeout,
+// servers.ErrShutdownTimeout is returned, once it elapses.
+// The shutdown goes through the phases reported to Hooks.OnPhase, see ShutdownPhase.
+func (s *Server) Stop(ctx context.Context) (err error) {
+	ctx, span := s.tracer.Start(ctx, "http server stop")
+	defer func() {
+		servers.EndSpan(span, err)
+	}()
+	stopCtx := ctx
 
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
 	if s.shutdown {
-		return nil
+		return servers.ErrAlreadyStopped
 	}
 
-	s.http.ErrorLog.Println("starting shutdown http server")
+	s.log().Info("starting shutdown http server", "state", s.State())
+	started := time.Now()
 	s.shutdown = true
+	close(s.stopping)
+	s.transit(StateDraining)
+	defer func() {
+		s.phase(stopCtx, PhasePostStop)
+		if err != nil {
+			s.transit(StateFailed)
+		} else {
+			s.transit(StateStopped)
+		}
+		s.hooks.stop(err)
+		s.exit(err)
+	}()
+	s.notifyStopping()
+
+	_, preStopSpan := s.tracer.Start(ctx, "http server pre-stop delay")
+	s.preStopDelay(ctx)
+	preStopSpan.End()
 
 	var cancel context.CancelFunc
-	ctx, cancel = context.WithTimeout(context.Background(), s.stopTimeout)
+	if s.stopTimeout != 0 {
+		ctx, cancel = context.WithTimeout(ctx, s.stopTimeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
 	defer cancel()
 
-	err := s.http.Shutdown(ctx)
+	s.hooks.stopping(ctx)
+
+	var closeDeadline time.Time
+	defer func() {
+		s.stopCompanions(ctx, closeDeadline)
+	}()
+	defer s.close()
+
+	drainCtx := ctx
+	if s.drainTimeout != 0 {
+		var cancelDrain context.CancelFunc
+		drainCtx, cancelDrain = context.WithTimeout(ctx, s.drainTimeout)
+		defer cancelDrain()
+	}
+
+	s.phase(ctx, PhaseStopAccepting)
+	s.stopAccepting()
+	s.phase(ctx, PhasePreDrain)
+	s.phase(ctx, PhaseDrain)
+
+	_, drainSpan := s.tracer.Start(ctx, "http server drain")
+	stopProgress := s.reportProgress(started)
+	err = s.http.Shutdown(drainCtx)
+	if err == nil {
+		err = s.stopHijacked(drainCtx)
+	}
+	stopProgress()
+	servers.EndSpan(drainSpan, err)
 	if err == nil {
-		s.http.ErrorLog.Println("shutdown successful")
+		s.log().Info("shutdown successful", "duration", time.Since(started))
 		return nil
 	} else {
-		s.http.ErrorLog.Printf("shutdown error: %s", err.Error())
+		s.log().Error("shutdown error", "error", err)
 	}
 
-	closing := make(chan error)
+	s.phase(ctx, PhaseForceClose)
+	stragglers := s.tracker.stragglers()
+	active := s.tracker.activeRequests()
+	if len(stragglers) != 0 {
+		s.log().Error("closing stragglers", "count", len(stragglers), "oldest", stragglers[0].RemoteAddr,
+			"age", stragglers[0].Age)
+	}
+	s.hooks.forceClose(stragglers)
+
+	closing := make(chan error, 1)
 
-	timer := time.NewTimer(s.stopTimeout)
+	budget := servers.ForceCloseBudget(ctx)
+	closeDeadline = time.Now().Add(budget)
+	timer := time.NewTimer(budget)
 	defer timer.Stop()
 
+	_, closeSpan := s.tracer.Start(ctx, "http server close")
+	defer func() {
+		servers.EndSpan(closeSpan, err)
+	}()
+
 	go func() {
-		err = s.http.Close()
+		err := s.http.Close()
 		if err != nil {
-			err = xerrors.Errorf("error closing: %w", err)
+			err = fmt.Errorf("error closing: %w", err)
 		}
 		s.http.SetKeepAlivesEnabled(false)
+		if s.hijacked != HijackedIgnore {
+			if cut := s.tracker.closeHijacked(); cut != 0 {
+				s.log().Error("hijacked connections cut", "count", cut)
+			}
+		}
 		closing <- err
 		close(closing)
 	}()
@@ -115,19 +627,57 @@ func (s *Server) Stop(ctx context.Context) error {
 	select {
 	case err := <-closing:
 		if err != nil {
-			err = xerrors.Errorf("can't close http server: %w", err)
-			s.http.ErrorLog.Printf("closing error: %s", err.Error())
+			err = fmt.Errorf("can't close http server: %w", err)
+			s.log().Error("closing error", "error", err)
 		} else {
-			s.http.ErrorLog.Println("closing successful")
+			s.log().Info("closing successful", "duration", time.Since(started))
+			err = &ForcedCloseError{Elapsed: time.Since(started), ActiveRequests: active, Stragglers: stragglers}
 		}
 		return err
 	case <-timer.C:
-		err := xerrors.New("can't close http server, timeout exceeded")
-		s.http.ErrorLog.Printf("closing timeout exceeded error: %s", err.Error())
+		err := fmt.Errorf("can't close http server: %w", servers.ErrShutdownTimeout)
+		s.log().Error("closing timeout exceeded error", "error", err)
+		// The resources are released once the server is closed, so that nothing outlives Stop.
+		<-closing
 		return err
 	}
 }
 
+// preStopDelay waits PreStopDelay or until ctx is done, whichever is earlier.
+func (s *Server) preStopDelay(ctx context.Context) {
+	if s.preStop == 0 {
+		return
+	}
+
+	s.log().Info("waiting before shutdown", "delay", s.preStop)
+
+	timer := time.NewTimer(s.preStop)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+		s.log().Info("pre-stop delay interrupted")
+	}
+}
+
+// close releases the resources owned by the server.
+func (s *Server) close() {
+	if s.tickets != nil {
+		s.tickets.Close()
+	}
+
+	if s.clientCAs != nil {
+		s.clientCAs.Close()
+	}
+
+	if source := s.certificates.Load(); source != nil && source.closer != nil {
+		if err := source.closer.Close(); err != nil {
+			s.log().Error("release error", "error", err)
+		}
+	}
+}
+
 // New - constructor Server.
 func New(cfg Config) (*Server, error) {
 	if err := cfg.Validate(); err != nil {
@@ -135,17 +685,57 @@ func New(cfg Config) (*Server, error) {
 	}
 
 	server := &Server{
-		stopTimeout: cfg.StopTimeout,
-		mutex:       new(sync.RWMutex),
+		stopTimeout:   cfg.StopTimeout,
+		drainTimeout:  cfg.DrainTimeout,
+		progress:      cfg.StopProgressInterval,
+		preStop:       cfg.PreStopDelay,
+		mutex:         new(sync.RWMutex),
+		tracer:        servers.Tracer(cfg.TracerProvider),
+		listener:      cfg.Listener,
+		reusePort:     cfg.ReusePortListeners,
+		proxyProtocol: cfg.ProxyProtocol,
+		ipFilter:      cfg.IPFilter,
+		listenConfig:  net.ListenConfig{Control: cfg.ListenControl, KeepAlive: cfg.TCPKeepAlive},
+		bindRetry:     cfg.BindRetry,
+		hijacked:      cfg.HijackedConnections,
+		ready:         make(chan struct{}),
+		bindMutex:     new(sync.Mutex),
+		stopping:      make(chan struct{}),
+		stateMutex:    new(sync.RWMutex),
+		done:          make(chan struct{}),
+		exitOnce:      new(sync.Once),
+		hooks:         cfg.Hooks,
+		tracker:       newTracker(),
+	}
+
+	server.addrs = cfg.Addrs
+	if len(server.addrs) == 0 {
+		server.addrs = []string{cfg.Addr}
 	}
 
 	server.http = &http.Server{
-		Addr:    cfg.Addr,
-		Handler: cfg.Router,
+		Addr:        cfg.Addr,
+		Handler:     cfg.Router,
+		ConnState:   server.tracker.connState,
+		BaseContext: cfg.BaseContext,
+		ConnContext: cfg.ConnContext,
+	}
+	if cfg.ProxyProtocol != nil {
+		server.http.ConnContext = proxyConnContext(cfg.ConnContext)
+	}
+	if cfg.ConnState != nil {
+		server.http.ConnState = func(conn net.Conn, state http.ConnState) {
+			server.tracker.connState(conn, state)
+			cfg.ConnState(conn, state)
+		}
 	}
 
-	server.http.ErrorLog = Log.New(cfg.ErrorsOutput, "Golang HTTP standard server: ",
-		Log.LstdFlags|Log.Lmicroseconds|Log.Llongfile|Log.Lshortfile)
+	server.logger.Store(&cfg.Logger)
+	var errorLog servers.Logger = serverLogger{server: server}
+	if cfg.ErrorLogFilter != nil {
+		errorLog = newLogFilter(*cfg.ErrorLogFilter, errorLog)
+	}
+	server.http.ErrorLog = servers.StdLog(errorLog)
 
 	if cfg.ReadTimeout != 0 {
 		server.http.ReadTimeout = cfg.ReadTimeout
@@ -163,6 +753,52 @@ func New(cfg Config) (*Server, error) {
 		server.http.MaxHeaderBytes = cfg.MaxHeaderBytes
 	}
 
+	if cfg.SystemdNotify {
+		server.watchdog = make(chan struct{})
+	}
+
+	if cfg.SocketActivation {
+		listeners, err := systemd.Listeners()
+		if err != nil {
+			return nil, fmt.Errorf("can't get socket activation listeners: %w", err)
+		}
+		if len(listeners) == 0 {
+			return nil, errors.New("no socket activation listeners passed")
+		}
+		for _, listener := range listeners[1:] {
+			server.log().Info("unused socket activation listener closed", "addr", listener.Addr())
+			listener.Close()
+		}
+		server.listener = listeners[0]
+	}
+
+	if cfg.LoadShedding != nil {
+		server.shedder = newShedder(*cfg.LoadShedding)
+	}
+
+	if cfg.ConnLimit != nil {
+		server.connLimiter = newConnLimiter(*cfg.ConnLimit)
+	}
+
+	if cfg.TLS != nil {
+		if err := server.configureTLS(*cfg.TLS); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.HTTP2 != nil {
+		if err := server.configureHTTP2(*cfg.HTTP2); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.TLS != nil && cfg.TLS.SessionTickets != nil {
+		if err := server.configureSessionTickets(*cfg.TLS.SessionTickets); err != nil {
+			return nil, err
+		}
+	}
+
+	server.http.Handler = server.handler(cfg)
 	server.http.SetKeepAlivesEnabled(cfg.KeepAliveEnabled)
 
 	return server, nil
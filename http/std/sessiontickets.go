@@ -0,0 +1,154 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SessionTicketKeyProvider delivers an interface to a source of session ticket keys, e.g. shared across a fleet,
+// so that the tickets issued by one server are accepted by the rest.
+type SessionTicketKeyProvider interface {
+	// SessionTicketKeys returns the current keys, the first one encrypts the new tickets, all of them decrypt.
+	SessionTicketKeys(ctx context.Context) ([][32]byte, error)
+}
+
+// SessionTicketsConfig delivers a set of settings for the rotation of the session ticket keys.
+// The keys are taken from Provider every Interval, if Provider is nil, a random key is generated every Interval
+// and the previous one is kept to decrypt the tickets issued before, so the tickets live up to two intervals.
+type SessionTicketsConfig struct {
+	Interval time.Duration
+	Provider SessionTicketKeyProvider
+}
+
+// Validate validates SessionTicketsConfig according to predefined rules.
+func (c SessionTicketsConfig) Validate() error {
+	var errs []error
+
+	if c.Interval <= 0 {
+		errs = append(errs, errors.New("Interval must be positive"))
+	}
+	return errors.Join(errs...)
+}
+
+// randomTicketKeys is the default SessionTicketKeyProvider generating a random key on each call.
+type randomTicketKeys struct {
+	mutex *sync.Mutex
+	keys  [][32]byte
+}
+
+// SessionTicketKeys returns the new key followed by the previous one.
+func (k *randomTicketKeys) SessionTicketKeys(context.Context) ([][32]byte, error) {
+	var key [32]byte
+	if _, err := rand.Read(key[:]); err != nil {
+		return nil, fmt.Errorf("can't generate session ticket key: %w", err)
+	}
+
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	k.keys = append([][32]byte{key}, k.keys[:min(len(k.keys), 1)]...)
+	return k.keys, nil
+}
+
+// sessionTickets rotates the session ticket keys of tls.Config of the server.
+// net/http serves a clone of tls.Config, so the config with the current keys is handed to the handshakes
+// by GetConfigForClient.
+type sessionTickets struct {
+	server    *Server
+	provider  SessionTicketKeyProvider
+	base      *tls.Config
+	current   atomic.Pointer[tls.Config]
+	done      chan struct{}
+	closeOnce *sync.Once
+}
+
+// GetConfigForClient returns the config with the current keys.
+func (t *sessionTickets) GetConfigForClient(*tls.ClientHelloInfo) (*tls.Config, error) {
+	return t.current.Load(), nil
+}
+
+// Close stops the rotation.
+func (t *sessionTickets) Close() error {
+	t.closeOnce.Do(func() {
+		close(t.done)
+	})
+	return nil
+}
+
+// rotate replaces the config with the one using the keys taken from the provider.
+func (t *sessionTickets) rotate() error {
+	keys, err := t.provider.SessionTicketKeys(context.Background())
+	if err != nil {
+		return fmt.Errorf("can't get session ticket keys: %w", err)
+	}
+	if len(keys) == 0 {
+		return errors.New("can't get session ticket keys: no keys provided")
+	}
+
+	config := t.base.Clone()
+	config.SetSessionTicketKeys(keys)
+	t.current.Store(config)
+	return nil
+}
+
+// run rotates the keys every interval, until the rotation is closed.
+func (t *sessionTickets) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-t.done:
+			return
+		case <-ticker.C:
+			if err := t.rotate(); err != nil {
+				t.server.log().Error("session tickets rotation error", "error", err)
+			}
+		}
+	}
+}
+
+// configureSessionTickets starts the rotation of the session ticket keys of tls.Config of the server,
+// it is called once tls.Config is complete, HTTP/2 included.
+func (s *Server) configureSessionTickets(c SessionTicketsConfig) error {
+	tlsConfig := s.http.TLSConfig
+
+	provider := c.Provider
+	if provider == nil {
+		provider = &randomTicketKeys{mutex: new(sync.Mutex)}
+	}
+
+	tickets := &sessionTickets{
+		server:    s,
+		provider:  provider,
+		base:      tlsConfig.Clone(),
+		done:      make(chan struct{}),
+		closeOnce: new(sync.Once),
+	}
+
+	// The config returned by GetConfigForClient negotiates ALPN, so it advertises the protocols added by ServeTLS.
+	protocols := s.http.Protocols
+	if (protocols == nil || protocols.HTTP2()) && !slices.Contains(tickets.base.NextProtos, "h2") {
+		tickets.base.NextProtos = append(tickets.base.NextProtos, "h2")
+	}
+	if (protocols == nil || protocols.HTTP1()) && !slices.Contains(tickets.base.NextProtos, "http/1.1") {
+		tickets.base.NextProtos = append(tickets.base.NextProtos, "http/1.1")
+	}
+
+	if err := tickets.rotate(); err != nil {
+		return err
+	}
+	tlsConfig.GetConfigForClient = tickets.GetConfigForClient
+	s.tickets = tickets
+
+	go tickets.run(c.Interval)
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package server
+
+import (
+	"strconv"
+	"time"
+)
+
+// setting is the scalar field of Config settable from the text (environment variable, flag),
+// name is in kebab case, e.g. "read-timeout".
+type setting struct {
+	name  string
+	usage string
+	value value
+}
+
+// value is the settable field, it satisfies flag.Value.
+type value interface {
+	String() string
+	Set(text string) error
+}
+
+// settings returns the scalar fields of the config settable from the text.
+func (c *Config) settings() []setting {
+	return []setting{
+		{"addr", "listen address, host:port or unix://path", (*stringValue)(&c.Addr)},
+		{"read-timeout", "maximum duration for reading the entire request", (*durationValue)(&c.ReadTimeout)},
+		{"read-header-timeout", "maximum duration for reading the request headers",
+			(*durationValue)(&c.ReadHeaderTimeout)},
+		{"write-timeout", "maximum duration before timing out writes of the response", (*durationValue)(&c.WriteTimeout)},
+		{"idle-timeout", "maximum duration to wait for the next request on keep-alive connections",
+			(*durationValue)(&c.IdleTimeout)},
+		{"stop-timeout", "maximum duration of the graceful shutdown", (*durationValue)(&c.StopTimeout)},
+		{"pre-stop-delay", "delay before the shutdown starts", (*durationValue)(&c.PreStopDelay)},
+		{"max-header-bytes", "maximum size of the request headers", (*intValue)(&c.MaxHeaderBytes)},
+		{"keep-alive", "enable keep-alive connections", (*boolValue)(&c.KeepAliveEnabled)},
+		{"socket-activation", "serve the listener passed by systemd socket activation",
+			(*boolValue)(&c.SocketActivation)},
+		{"systemd-notify", "notify systemd of readiness and stopping", (*boolValue)(&c.SystemdNotify)},
+		{"reuse-port-listeners", "number of SO_REUSEPORT listeners", (*intValue)(&c.ReusePortListeners)},
+		{"max-concurrent-requests", "maximum number of requests served at once", (*intValue)(&c.MaxConcurrentRequests)},
+		{"max-request-body-bytes", "maximum size of the request body", (*int64Value)(&c.MaxRequestBodyBytes)},
+		{"handler-timeout", "maximum duration of the handler", (*durationValue)(&c.HandlerTimeout)},
+	}
+}
+
+// stringValue is the settable string.
+type stringValue string
+
+func (v *stringValue) String() string { return string(*v) }
+
+func (v *stringValue) Set(text string) error {
+	*v = stringValue(text)
+	return nil
+}
+
+// durationValue is the settable time.Duration, e.g. "30s".
+type durationValue time.Duration
+
+func (v *durationValue) String() string { return time.Duration(*v).String() }
+
+func (v *durationValue) Set(text string) error {
+	duration, err := time.ParseDuration(text)
+	if err != nil {
+		return err
+	}
+	*v = durationValue(duration)
+	return nil
+}
+
+// intValue is the settable int.
+type intValue int
+
+func (v *intValue) String() string { return strconv.Itoa(int(*v)) }
+
+func (v *intValue) Set(text string) error {
+	i, err := strconv.Atoi(text)
+	if err != nil {
+		return err
+	}
+	*v = intValue(i)
+	return nil
+}
+
+// int64Value is the settable int64.
+type int64Value int64
+
+func (v *int64Value) String() string { return strconv.FormatInt(int64(*v), 10) }
+
+func (v *int64Value) Set(text string) error {
+	i, err := strconv.ParseInt(text, 10, 64)
+	if err != nil {
+		return err
+	}
+	*v = int64Value(i)
+	return nil
+}
+
+// boolValue is the settable bool.
+type boolValue bool
+
+func (v *boolValue) String() string { return strconv.FormatBool(bool(*v)) }
+
+func (v *boolValue) Set(text string) error {
+	b, err := strconv.ParseBool(text)
+	if err != nil {
+		return err
+	}
+	*v = boolValue(b)
+	return nil
+}
+
+// IsBoolFlag allows the flag without the value, see flag.Value.
+func (v *boolValue) IsBoolFlag() bool { return true }
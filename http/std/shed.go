@@ -0,0 +1,116 @@
+package server
+
+import (
+	"errors"
+	"math/rand"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// latencyWeight is the weight of the latest request in the moving average latency.
+const latencyWeight = 0.1
+
+// maxShedFraction bounds the fraction of the rejected requests, so that the latency keeps being observed.
+const maxShedFraction = 0.9
+
+// LoadSheddingConfig delivers a set of settings for the adaptive load shedding of the requests.
+// Once the requests in flight exceed MaxInFlight or the moving average latency exceeds MaxLatency,
+// the fraction of the requests proportional to the excess is rejected with 503 and Retry-After:
+// the fraction reaches 0.9 at twice the threshold. Zero threshold is disabled.
+// OnShed, if set, is called on each rejected request, the rejections are also counted in Stats.
+type LoadSheddingConfig struct {
+	MaxInFlight int           `config:"max_in_flight"`
+	MaxLatency  time.Duration `config:"max_latency"`
+	OnShed      func(r *http.Request)
+}
+
+// Validate validates LoadSheddingConfig according to predefined rules.
+func (c LoadSheddingConfig) Validate() error {
+	var errs []error
+
+	if c.MaxInFlight < 0 || c.MaxLatency < 0 {
+		errs = append(errs, errors.New("MaxInFlight and MaxLatency can't be negative"))
+	}
+
+	if c.MaxInFlight == 0 && c.MaxLatency == 0 {
+		errs = append(errs, errors.New("at least one of MaxInFlight and MaxLatency must be set"))
+	}
+	return errors.Join(errs...)
+}
+
+// shedder predetermines the consistency of the adaptive load shedding.
+type shedder struct {
+	config   LoadSheddingConfig
+	inFlight int64
+	shed     uint64
+	mutex    *sync.Mutex
+	latency  float64
+}
+
+// fraction returns the fraction of the requests to reject under the current load.
+func (s *shedder) fraction() float64 {
+	var fraction float64
+	if s.config.MaxInFlight > 0 {
+		fraction = excess(float64(atomic.LoadInt64(&s.inFlight)), float64(s.config.MaxInFlight))
+	}
+
+	if s.config.MaxLatency > 0 {
+		s.mutex.Lock()
+		latency := s.latency
+		s.mutex.Unlock()
+
+		fraction = max(fraction, excess(latency, float64(s.config.MaxLatency)))
+	}
+	return fraction * maxShedFraction
+}
+
+// excess returns the relative excess of the value over the threshold bounded by [0, 1].
+func excess(value, threshold float64) float64 {
+	return min(max(value/threshold-1, 0), 1)
+}
+
+// observe adds the latency of the served request to the moving average.
+func (s *shedder) observe(latency time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.latency += latencyWeight * (float64(latency) - s.latency)
+}
+
+// wrap wraps the handler shedding the requests under overload.
+func (s *shedder) wrap(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fraction := s.fraction(); fraction > 0 && rand.Float64() < fraction {
+			atomic.AddUint64(&s.shed, 1)
+			if s.config.OnShed != nil {
+				s.config.OnShed(r)
+			}
+
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+			return
+		}
+
+		atomic.AddInt64(&s.inFlight, 1)
+		defer atomic.AddInt64(&s.inFlight, -1)
+
+		started := time.Now()
+		handler.ServeHTTP(w, r)
+		s.observe(time.Since(started))
+	})
+}
+
+// shedRequests returns the count of the rejected requests.
+func (s *shedder) shedRequests() uint64 {
+	return atomic.LoadUint64(&s.shed)
+}
+
+// newShedder - constructor shedder.
+func newShedder(c LoadSheddingConfig) *shedder {
+	return &shedder{
+		config: c,
+		mutex:  new(sync.Mutex),
+	}
+}
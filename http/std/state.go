@@ -0,0 +1,101 @@
+package server
+
+import (
+	"fmt"
+	"github.com/golang-mixins/servers"
+)
+
+// State represents the lifecycle state of the server.
+type State int
+
+// States of the server.
+const (
+	StateNew State = iota
+	StateListening
+	StateServing
+	StateDraining
+	StateStopped
+	StateFailed
+)
+
+// String returns the name of the state.
+func (s State) String() string {
+	switch s {
+	case StateNew:
+		return "new"
+	case StateListening:
+		return "listening"
+	case StateServing:
+		return "serving"
+	case StateDraining:
+		return "draining"
+	case StateStopped:
+		return "stopped"
+	case StateFailed:
+		return "failed"
+	default:
+		return "unknown"
+	}
+}
+
+// transitions lists the states legally following each state.
+var transitions = map[State][]State{
+	StateNew:       {StateListening, StateDraining, StateFailed},
+	StateListening: {StateServing, StateDraining, StateFailed},
+	StateServing:   {StateDraining, StateFailed},
+	StateDraining:  {StateStopped, StateFailed},
+	StateStopped:   {},
+	StateFailed:    {StateDraining},
+}
+
+// State returns the current lifecycle state of the server.
+func (s *Server) State() State {
+	s.stateMutex.RLock()
+	defer s.stateMutex.RUnlock()
+
+	return s.state
+}
+
+// transit moves the server into the state, illegal transitions are refused.
+func (s *Server) transit(to State) error {
+	s.stateMutex.Lock()
+	defer s.stateMutex.Unlock()
+
+	for _, legal := range transitions[s.state] {
+		if legal == to {
+			s.log().Info("state changed", "from", s.state, "to", to)
+			s.state = to
+			return nil
+		}
+	}
+	if s.state == StateDraining || s.state == StateStopped {
+		return fmt.Errorf("illegal state transition from %s to %s: %w", s.state, to, servers.ErrServerClosed)
+	}
+	return fmt.Errorf("illegal state transition from %s to %s", s.state, to)
+}
+
+// Done returns the channel, which is closed once the server has terminated: either Serve has failed,
+// or the server has been stopped by Stop.
+func (s *Server) Done() <-chan struct{} {
+	return s.done
+}
+
+// Err returns the error the server has terminated with: the error of Serve, if it has failed,
+// otherwise the error of Stop. Nil is returned until Done is closed.
+func (s *Server) Err() error {
+	s.stateMutex.RLock()
+	defer s.stateMutex.RUnlock()
+
+	return s.err
+}
+
+// exit records the termination of the server, only the first one counts.
+func (s *Server) exit(err error) {
+	s.exitOnce.Do(func() {
+		s.stateMutex.Lock()
+		s.err = err
+		s.stateMutex.Unlock()
+
+		close(s.done)
+	})
+}
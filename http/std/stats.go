@@ -0,0 +1,119 @@
+package server
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Stats represents the snapshot of the connections and requests of the server.
+// Connections counts the open connections by their current state (new, active, idle).
+// ShedRequests counts the requests rejected by the load shedding.
+// OpenHijackedConnections counts the hijacked connections, which aren't closed yet,
+// they are tracked unless Config.HijackedConnections is HijackedIgnore.
+type Stats struct {
+	OpenConnections         int
+	Connections             map[http.ConnState]int
+	HijackedConnections     uint64
+	OpenHijackedConnections int
+	ActiveRequests          int64
+	Requests                uint64
+	ShedRequests            uint64
+}
+
+// StatsSource delivers an interface to the source of Stats, which is consumed by the metrics exporters.
+type StatsSource interface {
+	// Stats returns the snapshot of the connections and requests.
+	Stats() Stats
+}
+
+// connInfo is the state of the tracked connection and the time it was opened.
+type connInfo struct {
+	state  http.ConnState
+	opened time.Time
+}
+
+// tracker tracks the connections and requests of the server.
+type tracker struct {
+	mutex         *sync.Mutex
+	conns         map[net.Conn]connInfo
+	hijacked      uint64
+	hijackedConns map[*trackedConn]struct{}
+	active        int64
+	requests      uint64
+}
+
+// connState tracks the state of the connection, it is used as http.Server.ConnState.
+func (t *tracker) connState(conn net.Conn, state http.ConnState) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	switch state {
+	case http.StateHijacked:
+		t.hijacked++
+		delete(t.conns, conn)
+		if tracked, ok := hijackedConn(conn); ok {
+			t.hijackedConns[tracked] = struct{}{}
+		}
+	case http.StateClosed:
+		delete(t.conns, conn)
+	default:
+		info, ok := t.conns[conn]
+		if !ok {
+			info.opened = time.Now()
+		}
+		info.state = state
+		t.conns[conn] = info
+	}
+}
+
+// wrap counts the requests served by the handler.
+func (t *tracker) wrap(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddUint64(&t.requests, 1)
+		atomic.AddInt64(&t.active, 1)
+		defer atomic.AddInt64(&t.active, -1)
+
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// stats returns the snapshot of the connections and requests.
+func (t *tracker) stats() Stats {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	stats := Stats{
+		OpenConnections:         len(t.conns),
+		Connections:             make(map[http.ConnState]int, 3),
+		HijackedConnections:     t.hijacked,
+		OpenHijackedConnections: len(t.hijackedConns),
+		ActiveRequests:          atomic.LoadInt64(&t.active),
+		Requests:                atomic.LoadUint64(&t.requests),
+	}
+	for _, info := range t.conns {
+		stats.Connections[info.state]++
+	}
+	return stats
+}
+
+// newTracker - constructor tracker.
+func newTracker() *tracker {
+	return &tracker{
+		mutex:         new(sync.Mutex),
+		conns:         make(map[net.Conn]connInfo),
+		hijackedConns: make(map[*trackedConn]struct{}),
+	}
+}
+
+// Stats returns the snapshot of the connections and requests of the server.
+// OpenConnections reaching zero after Stop started means draining is complete.
+func (s *Server) Stats() Stats {
+	stats := s.tracker.stats()
+	if s.shedder != nil {
+		stats.ShedRequests = s.shedder.shedRequests()
+	}
+	return stats
+}
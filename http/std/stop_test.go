@@ -0,0 +1,141 @@
+package server_test
+
+import (
+	"context"
+	"errors"
+	"github.com/golang-mixins/servers"
+	"github.com/golang-mixins/servers/http/memory"
+	server "github.com/golang-mixins/servers/http/std"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// serveMemory serves the router in memory with StopTimeout and returns the served server.
+func serveMemory(t *testing.T, router http.Handler, stopTimeout time.Duration) (*memory.Server, chan error) {
+	t.Helper()
+
+	cfg := server.DefaultConfig("", router)
+	cfg.StopTimeout = stopTimeout
+	cfg.Logger = servers.NewLogger(io.Discard, "")
+
+	srv, err := memory.New(cfg)
+	if err != nil {
+		t.Fatalf("can't create server: %v", err)
+	}
+
+	serving := make(chan error, 1)
+	go func() {
+		serving <- srv.Serve()
+	}()
+	<-srv.Ready()
+	return srv, serving
+}
+
+func TestStopDrainsRequests(t *testing.T) {
+	started := make(chan struct{})
+	srv, serving := serveMemory(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(50 * time.Millisecond)
+		io.WriteString(w, "ok")
+	}), time.Second)
+
+	responses := make(chan error, 1)
+	go func() {
+		response, err := srv.Client().Get(srv.URL())
+		if err == nil {
+			response.Body.Close()
+		}
+		responses <- err
+	}()
+	<-started
+
+	if err := srv.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	if err := <-responses; err != nil {
+		t.Fatalf("request in flight failed: %v", err)
+	}
+	if err := <-serving; err != nil {
+		t.Fatalf("Serve: %v", err)
+	}
+
+	if err := srv.Stop(context.Background()); !errors.Is(err, servers.ErrAlreadyStopped) {
+		t.Fatalf("second Stop: got %v, want ErrAlreadyStopped", err)
+	}
+}
+
+func TestStopClosesStragglers(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+
+	srv, serving := serveMemory(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	}), 50*time.Millisecond)
+
+	go func() {
+		response, err := srv.Client().Get(srv.URL())
+		if err == nil {
+			response.Body.Close()
+		}
+	}()
+	<-started
+
+	stopped := time.Now()
+	err := srv.Stop(context.Background())
+
+	var forced *server.ForcedCloseError
+	if !errors.As(err, &forced) || !errors.Is(err, servers.ErrShutdownTimeout) {
+		t.Fatalf("Stop: got %v, want ForcedCloseError", err)
+	}
+	if len(forced.Stragglers) != 1 || forced.ActiveRequests != 1 {
+		t.Fatalf("got %d stragglers and %d requests, want 1 and 1",
+			len(forced.Stragglers), forced.ActiveRequests)
+	}
+	if elapsed := time.Since(stopped); elapsed > 50*time.Millisecond+servers.ForceCloseTimeout {
+		t.Fatalf("Stop took %s", elapsed)
+	}
+	if err = <-serving; err != nil {
+		t.Fatalf("Serve: %v", err)
+	}
+}
+
+func TestStopInterruptsBindRetries(t *testing.T) {
+	busy, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("can't listen: %v", err)
+	}
+	defer busy.Close()
+
+	cfg := server.DefaultConfig(busy.Addr().String(), http.NotFoundHandler())
+	cfg.Logger = servers.NewLogger(io.Discard, "")
+	cfg.BindRetry = &server.BindRetryConfig{Attempts: 100, Backoff: 50 * time.Millisecond}
+
+	srv, err := server.New(cfg)
+	if err != nil {
+		t.Fatalf("can't create server: %v", err)
+	}
+
+	listening := make(chan error, 1)
+	go func() {
+		listening <- srv.Listen()
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	if err = srv.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+
+	select {
+	case err = <-listening:
+		if !errors.Is(err, servers.ErrServerClosed) {
+			t.Fatalf("Listen: got %v, want ErrServerClosed", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("bind retries aren't interrupted by Stop")
+	}
+}
@@ -0,0 +1,71 @@
+package server
+
+import (
+	"fmt"
+	"github.com/golang-mixins/servers"
+	"net"
+	"net/http"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// Straggler describes the connection, which remained open, once the drain is exceeded, and was closed forcibly.
+// State is http.StateHijacked for the hijacked connections (tracked unless Config.HijackedConnections is
+// HijackedIgnore), Age is the time elapsed since the connection was accepted.
+type Straggler struct {
+	RemoteAddr net.Addr
+	State      http.ConnState
+	Age        time.Duration
+}
+
+// ForcedCloseError is returned by Stop, once the drain is exceeded and the remaining connections are closed,
+// it reports the stragglers (the oldest first) and unwraps to servers.ErrShutdownTimeout.
+type ForcedCloseError struct {
+	Elapsed        time.Duration
+	ActiveRequests int64
+	Stragglers     []Straggler
+}
+
+// Error returns the message of the error.
+func (e *ForcedCloseError) Error() string {
+	return fmt.Sprintf("http server closed forcibly, %d connections and %d requests remained after %s: %s",
+		len(e.Stragglers), e.ActiveRequests, e.Elapsed.Round(time.Millisecond), servers.ErrShutdownTimeout)
+}
+
+// Unwrap returns servers.ErrShutdownTimeout.
+func (e *ForcedCloseError) Unwrap() error {
+	return servers.ErrShutdownTimeout
+}
+
+// stragglers returns the connections open, including the tracked hijacked ones, the oldest first.
+// The connections are snapshotted under the lock, RemoteAddr is called after it's released.
+func (t *tracker) stragglers() []Straggler {
+	t.mutex.Lock()
+	now := time.Now()
+	conns := make([]net.Conn, 0, len(t.conns)+len(t.hijackedConns))
+	stragglers := make([]Straggler, 0, len(t.conns)+len(t.hijackedConns))
+	for conn, info := range t.conns {
+		conns = append(conns, conn)
+		stragglers = append(stragglers, Straggler{State: info.state, Age: now.Sub(info.opened)})
+	}
+	for conn := range t.hijackedConns {
+		conns = append(conns, conn)
+		stragglers = append(stragglers, Straggler{State: http.StateHijacked, Age: now.Sub(conn.opened)})
+	}
+	t.mutex.Unlock()
+
+	for i, conn := range conns {
+		stragglers[i].RemoteAddr = conn.RemoteAddr()
+	}
+
+	sort.Slice(stragglers, func(i, j int) bool {
+		return stragglers[i].Age > stragglers[j].Age
+	})
+	return stragglers
+}
+
+// activeRequests returns the number of the requests in flight.
+func (t *tracker) activeRequests() int64 {
+	return atomic.LoadInt64(&t.active)
+}
@@ -0,0 +1,57 @@
+package server
+
+import (
+	"github.com/golang-mixins/servers/systemd"
+	"time"
+)
+
+// notifyReady notifies systemd that the server is ready and starts the watchdog notifications, if enabled.
+func (s *Server) notifyReady() {
+	if s.watchdog == nil {
+		return
+	}
+
+	if _, err := systemd.Notify(systemd.Ready); err != nil {
+		s.log().Error("systemd notify error", "error", err)
+	}
+
+	interval, err := systemd.WatchdogInterval()
+	if err != nil {
+		s.log().Error("systemd watchdog error", "error", err)
+		return
+	}
+
+	if interval != 0 {
+		go s.notifyWatchdog(interval / 2)
+	}
+}
+
+// notifyWatchdog notifies systemd watchdog periodically until the server is stopping.
+func (s *Server) notifyWatchdog(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.watchdog:
+			return
+		case <-ticker.C:
+			if _, err := systemd.Notify(systemd.Watchdog); err != nil {
+				s.log().Error("systemd watchdog notify error", "error", err)
+			}
+		}
+	}
+}
+
+// notifyStopping notifies systemd that the server is stopping and stops the watchdog notifications, if enabled.
+func (s *Server) notifyStopping() {
+	if s.watchdog == nil {
+		return
+	}
+
+	close(s.watchdog)
+
+	if _, err := systemd.Notify(systemd.Stopping); err != nil {
+		s.log().Error("systemd notify error", "error", err)
+	}
+}
@@ -0,0 +1,390 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"github.com/golang-mixins/servers/certs"
+	"golang.org/x/crypto/acme"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"time"
+)
+
+// TLSConfig delivers a set of TLS settings for server implementation.
+// Certificates are taken from exactly one source: CertFile and KeyFile (reloaded from disk every ReloadInterval,
+// if set), Autocert, Provider or SelfSigned, which generates the certificate for localhost in memory
+// for the local development (see Config.WithDevTLS).
+// Certificates, if set, hold the key pairs selected by the server name of the handshake (see certs.SNI),
+// they are reloaded like CertFile and KeyFile, while the source above, if set, serves the rest of the names.
+// ClientAuth together with ClientCAs (or ClientCAFile) enables mutual TLS authentication.
+// ClientCAFile is reloaded from disk every ClientCAReloadInterval, if it is set, the client certificates are verified
+// against the current pool by the server then, VerifyPeerCertificate is called on every connection (the resumed
+// ones included) with the chains verified by the server.
+// Revocation, if set, rejects the client certificates revoked in any of the verified chains, e.g. certs.CRLFile
+// or certs.OCSP. Both are checked on the resumed sessions too, VerifyConnection is called after them.
+// MinVersion, CurvePreferences and CipherSuites, if set, are passed to tls.Config as is,
+// the Go defaults apply otherwise.
+// CipherSuites are limited to the secure ones (see tls.CipherSuites) and don't apply to TLS 1.3.
+// NextProtos, if set, are the ALPN protocols advertised in order of preference: "h2" and "http/1.1" select
+// the served HTTP versions (e.g. only "h2" refuses HTTP/1.1 clients), the rest are advertised for the multiplexers
+// in front of the server, the connections negotiating them are served as HTTP/1.1.
+// SessionTickets, if set, rotates the session ticket keys, otherwise crypto/tls rotates them daily on its own.
+// KeyLogWriter, if set, receives the TLS secrets in NSS key log format, e.g. to decrypt captures in Wireshark.
+// It compromises the security of the connections, so it must be enabled explicitly by InsecureKeyLog.
+type TLSConfig struct {
+	CertFile               string        `config:"cert_file"`
+	KeyFile                string        `config:"key_file"`
+	ReloadInterval         time.Duration `config:"reload_interval"`
+	Autocert               *AutocertConfig
+	Provider               certs.Provider
+	SelfSigned             bool `config:"self_signed"`
+	Certificates           map[string]KeyPair
+	ClientCAFile           string `config:"client_ca_file"`
+	ClientCAs              *x509.CertPool
+	ClientAuth             tls.ClientAuthType `config:"client_auth"`
+	ClientCAReloadInterval time.Duration      `config:"client_ca_reload_interval"`
+	Revocation             certs.RevocationChecker
+	VerifyPeerCertificate  func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error
+	VerifyConnection       func(state tls.ConnectionState) error
+	MinVersion             uint16 `config:"min_version"`
+	CurvePreferences       []tls.CurveID
+	CipherSuites           []uint16 `config:"cipher_suites"`
+	NextProtos             []string `config:"next_protos"`
+	SessionTickets         *SessionTicketsConfig
+	KeyLogWriter           io.Writer
+	InsecureKeyLog         bool
+}
+
+// Validate validates TLSConfig according to predefined rules.
+func (c TLSConfig) Validate() error {
+	var errs []error
+
+	sources := 0
+	if c.CertFile != "" || c.KeyFile != "" {
+		sources++
+	}
+	if c.Autocert != nil {
+		sources++
+	}
+	if c.Provider != nil {
+		sources++
+	}
+	if c.SelfSigned {
+		sources++
+	}
+
+	if sources > 1 {
+		errs = append(errs, errors.New("only one of CertFile and KeyFile, Autocert, Provider, SelfSigned can be set"))
+	}
+
+	switch {
+	case c.Autocert != nil:
+		if err := c.Autocert.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("Autocert: %w", err))
+		}
+	case c.Provider == nil && !c.SelfSigned && len(c.Certificates) == 0:
+		if c.CertFile == "" {
+			errs = append(errs, errors.New("CertFile can't be empty"))
+		}
+
+		if c.KeyFile == "" {
+			errs = append(errs, errors.New("KeyFile can't be empty"))
+		}
+	}
+
+	if c.ReloadInterval < 0 {
+		errs = append(errs, errors.New("ReloadInterval can't be negative"))
+	}
+
+	if c.ReloadInterval != 0 && c.CertFile == "" && len(c.Certificates) == 0 {
+		errs = append(errs,
+			errors.New("ReloadInterval can be set only together with CertFile and KeyFile, or Certificates"))
+	}
+
+	if c.Autocert != nil && len(c.Certificates) != 0 {
+		errs = append(errs, errors.New("Certificates can't be set together with Autocert"))
+	}
+
+	for name, pair := range c.Certificates {
+		if err := pair.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("Certificates %q: %w", name, err))
+		}
+	}
+
+	if c.ClientCAFile != "" && c.ClientCAs != nil {
+		errs = append(errs, errors.New("ClientCAFile and ClientCAs can't be set together"))
+	}
+
+	if c.ClientAuth >= tls.VerifyClientCertIfGiven && c.ClientCAFile == "" && c.ClientCAs == nil {
+		errs = append(errs, errors.New("ClientCAs can't be empty when ClientAuth verifies client certificates"))
+	}
+
+	if c.ClientCAReloadInterval < 0 {
+		errs = append(errs, errors.New("ClientCAReloadInterval can't be negative"))
+	}
+
+	if c.ClientCAReloadInterval != 0 && c.ClientCAFile == "" {
+		errs = append(errs, errors.New("ClientCAReloadInterval can be set only together with ClientCAFile"))
+	}
+
+	if c.Revocation != nil && c.ClientAuth < tls.VerifyClientCertIfGiven {
+		errs = append(errs, errors.New("Revocation can be set only when ClientAuth verifies client certificates"))
+	}
+
+	if c.MinVersion != 0 && (c.MinVersion < tls.VersionTLS10 || c.MinVersion > tls.VersionTLS13) {
+		errs = append(errs, fmt.Errorf("unknown MinVersion %#04x", c.MinVersion))
+	}
+
+	if len(c.CipherSuites) != 0 && c.MinVersion == tls.VersionTLS13 {
+		errs = append(errs, errors.New("CipherSuites can't be set together with MinVersion TLS 1.3"))
+	}
+
+	for _, id := range c.CipherSuites {
+		if !secureCipherSuite(id) {
+			errs = append(errs, fmt.Errorf("CipherSuites: %s isn't secure or is unknown", tls.CipherSuiteName(id)))
+		}
+	}
+
+	if len(c.NextProtos) != 0 && !slices.Contains(c.NextProtos, "h2") && !slices.Contains(c.NextProtos, "http/1.1") {
+		errs = append(errs, errors.New("NextProtos must contain h2 or http/1.1"))
+	}
+
+	if c.KeyLogWriter != nil && !c.InsecureKeyLog {
+		errs = append(errs, errors.New("KeyLogWriter can be set only together with InsecureKeyLog"))
+	}
+
+	if c.SessionTickets != nil {
+		if err := c.SessionTickets.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("SessionTickets: %w", err))
+		}
+	}
+
+	for _, proto := range c.NextProtos {
+		if proto == "" || len(proto) > 255 {
+			errs = append(errs, fmt.Errorf("NextProtos: invalid protocol %q", proto))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// secureCipherSuite reports whether the cipher suite is one of the secure ones implemented by crypto/tls.
+func secureCipherSuite(id uint16) bool {
+	for _, suite := range tls.CipherSuites() {
+		if suite.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// KeyPair delivers the files of the certificate and its key.
+type KeyPair struct {
+	CertFile string
+	KeyFile  string
+}
+
+// Validate validates KeyPair according to predefined rules.
+func (p KeyPair) Validate() error {
+	var errs []error
+
+	if p.CertFile == "" {
+		errs = append(errs, errors.New("CertFile can't be empty"))
+	}
+
+	if p.KeyFile == "" {
+		errs = append(errs, errors.New("KeyFile can't be empty"))
+	}
+	return errors.Join(errs...)
+}
+
+// certificates is the source of the served certificates, which is replaced by Reload.
+// Closer, if set, releases the source once it is replaced or the server is stopped.
+type certificates struct {
+	get    func(hello *tls.ClientHelloInfo) (*tls.Certificate, error)
+	closer io.Closer
+}
+
+// newCertificates creates the source of the certificates according to TLSConfig, except Autocert.
+func (s *Server) newCertificates(c TLSConfig) (*certificates, error) {
+	var (
+		fallback certs.Provider
+		closers  closers
+	)
+	switch {
+	case c.Provider != nil:
+		fallback = c.Provider
+	case c.SelfSigned:
+		provider, err := certs.NewSelfSigned()
+		if err != nil {
+			return nil, fmt.Errorf("can't generate self-signed certificate: %w", err)
+		}
+		s.log().Info("self-signed certificate generated, don't use it in production")
+		fallback = provider
+	case c.CertFile != "":
+		provider, closer, err := s.newKeyPair(KeyPair{CertFile: c.CertFile, KeyFile: c.KeyFile}, c.ReloadInterval)
+		if err != nil {
+			return nil, err
+		}
+		fallback = provider
+		closers = append(closers, closer)
+	}
+
+	if len(c.Certificates) == 0 {
+		return &certificates{get: fallback.GetCertificate, closer: closers.orNil()}, nil
+	}
+
+	providers := make(map[string]certs.Provider, len(c.Certificates))
+	for name, pair := range c.Certificates {
+		provider, closer, err := s.newKeyPair(pair, c.ReloadInterval)
+		if err != nil {
+			closers.Close()
+			return nil, fmt.Errorf("certificate of %q: %w", name, err)
+		}
+		providers[name] = provider
+		closers = append(closers, closer)
+	}
+
+	sni, err := certs.NewSNI(providers, fallback)
+	if err != nil {
+		closers.Close()
+		return nil, fmt.Errorf("can't create certificates provider: %w", err)
+	}
+	return &certificates{get: sni.GetCertificate, closer: closers.orNil()}, nil
+}
+
+// newKeyPair creates the provider of the key pair, which is reloaded every interval, if it is set.
+// The closer is nil, unless the key pair is reloaded.
+func (s *Server) newKeyPair(pair KeyPair, interval time.Duration) (certs.Provider, io.Closer, error) {
+	if interval != 0 {
+		provider, err := certs.NewFile(certs.FileConfig{
+			CertFile: pair.CertFile,
+			KeyFile:  pair.KeyFile,
+			Interval: interval,
+			Logger:   serverLogger{server: s},
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("can't create certificates provider: %w", err)
+		}
+		return provider, provider, nil
+	}
+
+	certificate, err := tls.LoadX509KeyPair(pair.CertFile, pair.KeyFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("can't load key pair: %w", err)
+	}
+	return staticCertificate{certificate: &certificate}, nil, nil
+}
+
+// staticCertificate is the provider of the certificate loaded once.
+type staticCertificate struct {
+	certificate *tls.Certificate
+}
+
+// GetCertificate returns the certificate.
+func (c staticCertificate) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return c.certificate, nil
+}
+
+// closers releases the sources of the certificates together.
+type closers []io.Closer
+
+// Close closes the non-nil closers.
+func (c closers) Close() error {
+	var errs []error
+	for _, closer := range c {
+		if closer == nil {
+			continue
+		}
+		if err := closer.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// orNil returns nil instead of the closers, if there is nothing to close.
+func (c closers) orNil() io.Closer {
+	for _, closer := range c {
+		if closer != nil {
+			return c
+		}
+	}
+	return nil
+}
+
+// getCertificate returns the certificate of the current source.
+func (s *Server) getCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return s.certificates.Load().get(hello)
+}
+
+// configureTLS assembles tls.Config of the server according to TLSConfig.
+func (s *Server) configureTLS(c TLSConfig) error {
+	var tlsConfig *tls.Config
+	if c.Autocert != nil {
+		manager := c.Autocert.manager()
+		tlsConfig = manager.TLSConfig()
+
+		if c.Autocert.ChallengeAddr != "" {
+			s.addCompanion(c.Autocert.ChallengeAddr, manager.HTTPHandler(nil))
+		}
+	} else {
+		source, err := s.newCertificates(c)
+		if err != nil {
+			return err
+		}
+		s.certificates.Store(source)
+
+		tlsConfig = &tls.Config{GetCertificate: s.getCertificate}
+	}
+
+	if err := s.configureClientAuth(c, tlsConfig); err != nil {
+		return err
+	}
+	if c.MinVersion != 0 {
+		tlsConfig.MinVersion = c.MinVersion
+	}
+	if len(c.CurvePreferences) != 0 {
+		tlsConfig.CurvePreferences = c.CurvePreferences
+	}
+	tlsConfig.CipherSuites = c.CipherSuites
+
+	if c.KeyLogWriter != nil {
+		tlsConfig.KeyLogWriter = c.KeyLogWriter
+		s.log().Error("TLS key log enabled, the connections can be decrypted, don't use it in production")
+	}
+
+	if len(c.NextProtos) != 0 {
+		// autocert advertises acme-tls/1 for the TLS-ALPN-01 challenges, only it is kept of its protocols.
+		nextProtos := slices.Clone(c.NextProtos)
+		if slices.Contains(tlsConfig.NextProtos, acme.ALPNProto) {
+			nextProtos = append(nextProtos, acme.ALPNProto)
+		}
+		tlsConfig.NextProtos = nextProtos
+
+		protocols := new(http.Protocols)
+		protocols.SetHTTP1(slices.Contains(c.NextProtos, "http/1.1"))
+		protocols.SetHTTP2(slices.Contains(c.NextProtos, "h2"))
+		s.http.Protocols = protocols
+	}
+
+	s.http.TLSConfig = tlsConfig
+	return nil
+}
+
+// loadCertPool reads PEM encoded certificates from file into a new pool.
+func loadCertPool(file string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("can't read certificates file: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	if ok := pool.AppendCertsFromPEM(data); !ok {
+		return nil, fmt.Errorf("no valid certificates found in %s", file)
+	}
+	return pool, nil
+}
@@ -0,0 +1,114 @@
+package servers
+
+import (
+	"fmt"
+	"io"
+	Log "log"
+	"strings"
+)
+
+// Logger delivers an interface to the logger of the lifecycle messages of the launchers.
+// Printf receives the unstructured messages of the underlying servers, e.g. http.Server.ErrorLog,
+// so Logger also satisfies fasthttp.Logger.
+type Logger interface {
+	// Printf logs the unstructured message.
+	Printf(format string, v ...interface{})
+	// Info logs the lifecycle event with the attributes given as alternating keys and values.
+	Info(msg string, keysAndValues ...interface{})
+	// Error logs the failure with the attributes given as alternating keys and values.
+	Error(msg string, keysAndValues ...interface{})
+}
+
+// stdLogger predetermines the consistency of the implementation Logger on top of *log.Logger.
+type stdLogger struct {
+	logger *Log.Logger
+}
+
+// Printf logs the unstructured message.
+func (l stdLogger) Printf(format string, v ...interface{}) {
+	l.logger.Output(3, fmt.Sprintf(format, v...))
+}
+
+// Info logs the lifecycle event.
+func (l stdLogger) Info(msg string, keysAndValues ...interface{}) {
+	l.logger.Output(3, format(msg, keysAndValues))
+}
+
+// Error logs the failure.
+func (l stdLogger) Error(msg string, keysAndValues ...interface{}) {
+	l.logger.Output(3, format(msg, keysAndValues))
+}
+
+// format appends the attributes to the message as key=value pairs.
+func format(msg string, keysAndValues []interface{}) string {
+	var builder strings.Builder
+	builder.WriteString(msg)
+	for i := 0; i < len(keysAndValues); i += 2 {
+		if i+1 == len(keysAndValues) {
+			fmt.Fprintf(&builder, " %v", keysAndValues[i])
+			break
+		}
+		fmt.Fprintf(&builder, " %v=%v", keysAndValues[i], keysAndValues[i+1])
+	}
+	return builder.String()
+}
+
+// NewStdLogger - constructor Logger on top of *log.Logger.
+func NewStdLogger(logger *Log.Logger) Logger {
+	return stdLogger{logger: logger}
+}
+
+// NewLogger - constructor Logger writing to w with the prefix and the default flags.
+// NewStdLogger accepts *log.Logger with arbitrary prefix and flags.
+func NewLogger(w io.Writer, prefix string) Logger {
+	return NewStdLogger(Log.New(w, prefix, Log.LstdFlags|Log.Lmicroseconds|Log.Lshortfile))
+}
+
+// Verbosity defines the messages passed by the logger returned by WithVerbosity.
+type Verbosity int
+
+const (
+	// Verbose passes all the messages.
+	Verbose Verbosity = iota
+	// Quiet passes the failures only: Error and the unstructured messages of the underlying servers.
+	Quiet
+)
+
+// verbosityLogger predetermines the consistency of the implementation Logger, which filters the messages.
+type verbosityLogger struct {
+	Logger
+	verbosity Verbosity
+}
+
+// Info logs the lifecycle event, unless the logger is quiet.
+func (l verbosityLogger) Info(msg string, keysAndValues ...interface{}) {
+	if l.verbosity == Quiet {
+		return
+	}
+	l.Logger.Info(msg, keysAndValues...)
+}
+
+// WithVerbosity wraps the logger, so that it passes only the messages allowed by the verbosity.
+func WithVerbosity(logger Logger, verbosity Verbosity) Logger {
+	if verbosity == Verbose {
+		return logger
+	}
+	return verbosityLogger{Logger: logger, verbosity: verbosity}
+}
+
+// loggerWriter adapts Logger to io.Writer, each write is logged by Printf.
+type loggerWriter struct {
+	logger Logger
+}
+
+// Write logs p without the trailing newline.
+func (w loggerWriter) Write(p []byte) (int, error) {
+	w.logger.Printf("%s", strings.TrimSuffix(string(p), "\n"))
+	return len(p), nil
+}
+
+// StdLog returns *log.Logger forwarding its output to Printf of the logger,
+// for the underlying servers accepting only *log.Logger.
+func StdLog(logger Logger) *Log.Logger {
+	return Log.New(loggerWriter{logger: logger}, "", 0)
+}
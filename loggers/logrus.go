@@ -0,0 +1,46 @@
+package loggers
+
+import (
+	"fmt"
+	"github.com/golang-mixins/servers"
+	"github.com/sirupsen/logrus"
+)
+
+// logrusLogger predetermines the consistency of the implementation servers.Logger on top of logrus.FieldLogger.
+// The unstructured messages of the underlying servers are logged at the warning level.
+type logrusLogger struct {
+	logger logrus.FieldLogger
+}
+
+// Printf logs the unstructured message.
+func (l logrusLogger) Printf(format string, v ...interface{}) {
+	l.logger.Warnf(format, v...)
+}
+
+// Info logs the lifecycle event.
+func (l logrusLogger) Info(msg string, keysAndValues ...interface{}) {
+	l.logger.WithFields(fields(keysAndValues)).Info(msg)
+}
+
+// Error logs the failure.
+func (l logrusLogger) Error(msg string, keysAndValues ...interface{}) {
+	l.logger.WithFields(fields(keysAndValues)).Error(msg)
+}
+
+// fields converts the alternating keys and values to logrus.Fields, the value without a key is kept under "!BADKEY".
+func fields(keysAndValues []interface{}) logrus.Fields {
+	fields := make(logrus.Fields, len(keysAndValues)/2)
+	for i := 0; i < len(keysAndValues); i += 2 {
+		if i+1 == len(keysAndValues) {
+			fields["!BADKEY"] = keysAndValues[i]
+			break
+		}
+		fields[fmt.Sprint(keysAndValues[i])] = keysAndValues[i+1]
+	}
+	return fields
+}
+
+// NewLogrus - constructor servers.Logger on top of logrus.FieldLogger (*logrus.Logger or *logrus.Entry).
+func NewLogrus(logger logrus.FieldLogger) servers.Logger {
+	return logrusLogger{logger: logger}
+}
@@ -0,0 +1,35 @@
+// Package loggers provides the adapters of the third-party loggers to servers.Logger,
+// which keep the levels and the attributes of the lifecycle messages.
+package loggers
+
+import (
+	"github.com/golang-mixins/servers"
+	"go.uber.org/zap"
+)
+
+// zapLogger predetermines the consistency of the implementation servers.Logger on top of *zap.SugaredLogger.
+// The unstructured messages of the underlying servers are logged at the warning level.
+type zapLogger struct {
+	logger *zap.SugaredLogger
+}
+
+// Printf logs the unstructured message.
+func (l zapLogger) Printf(format string, v ...interface{}) {
+	l.logger.Warnf(format, v...)
+}
+
+// Info logs the lifecycle event.
+func (l zapLogger) Info(msg string, keysAndValues ...interface{}) {
+	l.logger.Infow(msg, keysAndValues...)
+}
+
+// Error logs the failure.
+func (l zapLogger) Error(msg string, keysAndValues ...interface{}) {
+	l.logger.Errorw(msg, keysAndValues...)
+}
+
+// NewZap - constructor servers.Logger on top of *zap.Logger.
+// The caller is reported as the launcher rather than the adapter.
+func NewZap(logger *zap.Logger) servers.Logger {
+	return zapLogger{logger: logger.WithOptions(zap.AddCallerSkip(1)).Sugar()}
+}
@@ -0,0 +1,198 @@
+// Package mock provides the fake implementation of servers.Launcher, which serves no sockets,
+// for the unit tests of the code orchestrating launchers (e.g. servers.Group and servers.Supervisor).
+package mock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golang-mixins/servers"
+	"sync"
+	"time"
+)
+
+// Config delivers a set of settings for Launcher implementation.
+// ServeErrors script the consecutive calls of Serve: the call, which has the non-nil error in the script, returns it
+// once ServeDelay elapses, the rest of the calls serve until Stop. The failed Serve may be called again,
+// like the launchers restarted by servers.Supervisor.
+// StopDelay is the time Stop takes, servers.ErrShutdownTimeout is returned, if ctx is done earlier,
+// otherwise StopError is returned.
+type Config struct {
+	ServeErrors []error
+	ServeDelay  time.Duration
+	StopDelay   time.Duration
+	StopError   error
+}
+
+// Validate validates Config according to predefined rules.
+func (c Config) Validate() error {
+	var errs []error
+
+	if c.ServeDelay < 0 {
+		errs = append(errs, errors.New("ServeDelay can't be negative"))
+	}
+
+	if c.StopDelay < 0 {
+		errs = append(errs, errors.New("StopDelay can't be negative"))
+	}
+	return errors.Join(errs...)
+}
+
+// Method is the method of Launcher recorded by Call.
+type Method string
+
+// Methods of Launcher.
+const (
+	MethodServe Method = "Serve"
+	MethodStop  Method = "Stop"
+)
+
+// Call represents the call of the method of Launcher, which has returned.
+type Call struct {
+	Method   Method
+	Started  time.Time
+	Duration time.Duration
+	Err      error
+}
+
+// Launcher predetermines the consistency of the implementation servers.Launcher, which follows the script of Config
+// and records the calls of its methods.
+// Using the methods of the structure, without being initialized by the New() constructor, will lead to panic.
+type Launcher struct {
+	config    Config
+	mutex     *sync.Mutex
+	calls     []Call
+	serves    int
+	stopped   bool
+	stopping  chan struct{}
+	ready     chan struct{}
+	readyOnce *sync.Once
+}
+
+// Serve serving the launcher according to the script, until it is stopped.
+// Nil is returned, once the launcher is stopped by Stop.
+func (l *Launcher) Serve() (err error) {
+	started := time.Now()
+	defer func() {
+		l.record(MethodServe, started, err)
+	}()
+
+	l.mutex.Lock()
+	call := l.serves
+	l.serves++
+	stopped := l.stopped
+	l.mutex.Unlock()
+
+	if stopped {
+		return fmt.Errorf("can't serve: %w", servers.ErrServerClosed)
+	}
+
+	if call < len(l.config.ServeErrors) && l.config.ServeErrors[call] != nil {
+		timer := time.NewTimer(l.config.ServeDelay)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+			return l.config.ServeErrors[call]
+		case <-l.stopping:
+			return nil
+		}
+	}
+
+	l.readyOnce.Do(func() {
+		close(l.ready)
+	})
+	<-l.stopping
+	return nil
+}
+
+// Stop stops the launcher within StopDelay.
+// servers.ErrAlreadyStopped is returned, if it is called again.
+func (l *Launcher) Stop(ctx context.Context) (err error) {
+	started := time.Now()
+	defer func() {
+		l.record(MethodStop, started, err)
+	}()
+
+	l.mutex.Lock()
+	if l.stopped {
+		l.mutex.Unlock()
+		return servers.ErrAlreadyStopped
+	}
+	l.stopped = true
+	close(l.stopping)
+	l.mutex.Unlock()
+
+	timer := time.NewTimer(l.config.StopDelay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return l.config.StopError
+	case <-ctx.Done():
+		return fmt.Errorf("mock stopped forcibly: %w", servers.ErrShutdownTimeout)
+	}
+}
+
+// Ready returns the channel, which is closed once Serve serves until Stop.
+func (l *Launcher) Ready() <-chan struct{} {
+	return l.ready
+}
+
+// Calls returns the copy of the calls of the methods, which have returned, in the order they have returned.
+func (l *Launcher) Calls() []Call {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return append([]Call(nil), l.calls...)
+}
+
+// CallsOf returns the number of the calls of the method, which have returned.
+func (l *Launcher) CallsOf(method Method) int {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	count := 0
+	for _, call := range l.calls {
+		if call.Method == method {
+			count++
+		}
+	}
+	return count
+}
+
+// Stopped reports whether Stop has been called.
+func (l *Launcher) Stopped() bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return l.stopped
+}
+
+// record records the call of the method.
+func (l *Launcher) record(method Method, started time.Time, err error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.calls = append(l.calls, Call{
+		Method:   method,
+		Started:  started,
+		Duration: time.Since(started),
+		Err:      err,
+	})
+}
+
+// New - constructor Launcher.
+func New(cfg Config) (*Launcher, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &Launcher{
+		config:    cfg,
+		mutex:     new(sync.Mutex),
+		stopping:  make(chan struct{}),
+		ready:     make(chan struct{}),
+		readyOnce: new(sync.Once),
+	}, nil
+}
@@ -0,0 +1,299 @@
+// Package server provides an implementation of interfaces servers, which serves gRPC and HTTP on a single port.
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golang-mixins/servers"
+	"github.com/soheilhy/cmux"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// defaultMatchTimeout bounds the matching of the connections, unless ReadHeaderTimeout or ReadTimeout is set.
+const defaultMatchTimeout = 10 * time.Second
+
+// Config delivers a set of settings for server implementation.
+// Connections carrying the gRPC content type are routed to the services registered by Register,
+// the rest are routed to Router.
+// The connections are matched within ReadHeaderTimeout (ReadTimeout, if it isn't set, or 10 seconds by default),
+// the ones which haven't sent enough to be matched by then are closed.
+// Stop is traced with the tracer of TracerProvider or of the global OpenTelemetry provider, if it is nil.
+type Config struct {
+	Addr              string
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	StopTimeout       time.Duration
+	MaxHeaderBytes    int
+	Logger            servers.Logger
+	Router            http.Handler
+	Register          func(registrar grpc.ServiceRegistrar)
+	GRPCOptions       []grpc.ServerOption
+	TracerProvider    trace.TracerProvider
+}
+
+// Validate validates Config according to predefined rules.
+func (c Config) Validate() error {
+	var errs []error
+
+	if c.Router == nil {
+		errs = append(errs, errors.New("Router can't be nil"))
+	}
+
+	if c.Register == nil {
+		errs = append(errs, errors.New("Register can't be nil"))
+	}
+
+	if c.StopTimeout < 0 {
+		errs = append(errs, errors.New("StopTimeout can't be negative"))
+	}
+
+	if err := servers.ValidateAddr(c.Addr); err != nil {
+		errs = append(errs, fmt.Errorf("Addr: %w", err))
+	}
+
+	if c.Logger == nil {
+		errs = append(errs, errors.New("Logger can't be nil"))
+	}
+	return errors.Join(errs...)
+}
+
+// Server predetermines the consistency of the implementation servers.Launcher.
+// Using the methods of the structure, without being initialized by the New() constructor, will lead to panic.
+type Server struct {
+	addr        string
+	stopTimeout time.Duration
+	readTimeout time.Duration
+	mutex       *sync.RWMutex
+	shutdown    bool
+	logger      servers.Logger
+	mux         cmux.CMux
+	http        *http.Server
+	grpc        *grpc.Server
+	tracer      trace.Tracer
+}
+
+// Serve serving the server.
+// Serve returns once any of the multiplexer, gRPC or HTTP servers exits.
+// Nil is returned, once the servers are closed by Stop. If any of them fails, the rest are stopped
+// and the listener is closed before the error is returned.
+func (s *Server) Serve() error {
+	s.mutex.RLock()
+	shutdown := s.shutdown
+	s.mutex.RUnlock()
+	if shutdown {
+		err := fmt.Errorf("can't serve: %w", servers.ErrServerClosed)
+		s.logger.Error("error Serve", "error", err)
+		return err
+	}
+
+	listener, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		err = &servers.BindError{Addr: s.addr, Err: err}
+		s.logger.Error("error Listen", "error", err)
+		return err
+	}
+	s.logger.Info("listening", "addr", listener.Addr())
+
+	s.mutex.Lock()
+	if s.shutdown {
+		s.mutex.Unlock()
+		listener.Close()
+		err := fmt.Errorf("can't serve: %w", servers.ErrServerClosed)
+		s.logger.Error("error Serve", "error", err)
+		return err
+	}
+	s.mux = cmux.New(listener)
+	s.mux.SetReadTimeout(s.readTimeout)
+	grpcListener := s.mux.MatchWithWriters(cmux.HTTP2MatchHeaderFieldSendSettings("content-type", "application/grpc"))
+	httpListener := s.mux.Match(cmux.Any())
+	s.mutex.Unlock()
+
+	serving := make(chan error, 3)
+	go func() {
+		serving <- s.grpc.Serve(grpcListener)
+	}()
+	go func() {
+		serving <- s.http.Serve(httpListener)
+	}()
+	go func() {
+		serving <- s.mux.Serve()
+	}()
+
+	err = <-serving
+	if isClosed(err) {
+		s.logger.Info("exit Serve, server closed")
+		return nil
+	}
+
+	s.grpc.Stop()
+	s.http.Close()
+	s.mux.Close()
+	listener.Close()
+	for i := 0; i < 2; i++ {
+		if serveErr := <-serving; !isClosed(serveErr) {
+			s.logger.Error("error serving after failure", "error", serveErr)
+		}
+	}
+
+	err = fmt.Errorf("error serving: %w", err)
+	s.logger.Error("error Serve", "error", err)
+	return err
+}
+
+// isClosed reports whether the error of the multiplexer, gRPC or HTTP server means it's closed.
+func isClosed(err error) bool {
+	return err == nil || errors.Is(err, http.ErrServerClosed) || errors.Is(err, grpc.ErrServerStopped) ||
+		errors.Is(err, cmux.ErrServerClosed) || errors.Is(err, cmux.ErrListenerClosed) || errors.Is(err, net.ErrClosed)
+}
+
+// Stop stops the server.
+// gRPC and HTTP servers are stopped gracefully and concurrently within StopTimeout or until ctx is done,
+// whichever is earlier, after that they are stopped forcibly and the listener is closed.
+func (s *Server) Stop(ctx context.Context) (err error) {
+	ctx, span := s.tracer.Start(ctx, "mux server stop")
+	defer func() {
+		servers.EndSpan(span, err)
+	}()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.shutdown {
+		return servers.ErrAlreadyStopped
+	}
+
+	s.logger.Info("starting shutdown mux server")
+	started := time.Now()
+	s.shutdown = true
+
+	var cancel context.CancelFunc
+	if s.stopTimeout != 0 {
+		ctx, cancel = context.WithTimeout(ctx, s.stopTimeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	var httpErr, grpcErr error
+	wg := new(sync.WaitGroup)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		httpErr = s.stopHTTP(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		grpcErr = s.stopGRPC(ctx)
+	}()
+	wg.Wait()
+
+	if s.mux != nil {
+		s.mux.Close()
+	}
+
+	switch {
+	case httpErr != nil && grpcErr != nil:
+		err := fmt.Errorf("can't stop mux server: %w; %w", httpErr, grpcErr)
+		s.logger.Error("shutdown error", "error", err)
+		return err
+	case httpErr != nil:
+		s.logger.Error("shutdown error", "error", httpErr)
+		return httpErr
+	case grpcErr != nil:
+		s.logger.Error("shutdown error", "error", grpcErr)
+		return grpcErr
+	}
+
+	s.logger.Info("shutdown successful", "duration", time.Since(started))
+	return nil
+}
+
+// stopHTTP stops the HTTP server gracefully until ctx is done, after that closes it.
+func (s *Server) stopHTTP(ctx context.Context) error {
+	err := s.http.Shutdown(ctx)
+	if err == nil {
+		return nil
+	}
+	s.logger.Error("http shutdown error", "error", err)
+
+	if err = s.http.Close(); err != nil {
+		return fmt.Errorf("can't close http server: %w", err)
+	}
+	return fmt.Errorf("http server closed forcibly: %w", servers.ErrShutdownTimeout)
+}
+
+// stopGRPC stops the gRPC server gracefully until ctx is done, after that stops it forcibly.
+func (s *Server) stopGRPC(ctx context.Context) error {
+	stopping := make(chan struct{})
+	go func() {
+		s.grpc.GracefulStop()
+		close(stopping)
+	}()
+
+	select {
+	case <-stopping:
+		return nil
+	case <-ctx.Done():
+		s.grpc.Stop()
+		<-stopping
+		return fmt.Errorf("grpc server stopped forcibly: %w", servers.ErrShutdownTimeout)
+	}
+}
+
+// New - constructor Server.
+func New(cfg Config) (*Server, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	server := &Server{
+		addr:        cfg.Addr,
+		stopTimeout: cfg.StopTimeout,
+		readTimeout: defaultMatchTimeout,
+		mutex:       new(sync.RWMutex),
+		tracer:      servers.Tracer(cfg.TracerProvider),
+	}
+
+	if cfg.ReadTimeout != 0 {
+		server.readTimeout = cfg.ReadTimeout
+	}
+	if cfg.ReadHeaderTimeout != 0 {
+		server.readTimeout = cfg.ReadHeaderTimeout
+	}
+
+	server.logger = cfg.Logger
+
+	server.http = &http.Server{
+		Handler:  cfg.Router,
+		ErrorLog: servers.StdLog(server.logger),
+	}
+
+	if cfg.ReadTimeout != 0 {
+		server.http.ReadTimeout = cfg.ReadTimeout
+	}
+	if cfg.ReadHeaderTimeout != 0 {
+		server.http.ReadHeaderTimeout = cfg.ReadHeaderTimeout
+	}
+	if cfg.WriteTimeout != 0 {
+		server.http.WriteTimeout = cfg.WriteTimeout
+	}
+	if cfg.IdleTimeout != 0 {
+		server.http.IdleTimeout = cfg.IdleTimeout
+	}
+	if cfg.MaxHeaderBytes != 0 {
+		server.http.MaxHeaderBytes = cfg.MaxHeaderBytes
+	}
+
+	server.grpc = grpc.NewServer(cfg.GRPCOptions...)
+	cfg.Register(server.grpc)
+
+	return server, nil
+}
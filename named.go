@@ -0,0 +1,68 @@
+package servers
+
+import (
+	"context"
+)
+
+// Named delivers an interface to a launcher, which is described by the name and the labels in logs, metrics and
+// errors (e.g. of Group).
+type Named interface {
+	Launcher
+	// Name returns the name of the launcher.
+	Name() string
+	// Labels returns the labels of the launcher.
+	Labels() map[string]string
+}
+
+// named predetermines the consistency of the implementation Named.
+type named struct {
+	launcher Launcher
+	name     string
+	labels   map[string]string
+}
+
+// Serve serving the launcher.
+func (n named) Serve() error {
+	return n.launcher.Serve()
+}
+
+// Stop stops the launcher.
+func (n named) Stop(ctx context.Context) error {
+	return n.launcher.Stop(ctx)
+}
+
+// Name returns the name of the launcher.
+func (n named) Name() string {
+	return n.name
+}
+
+// Labels returns the copy of the labels of the launcher.
+func (n named) Labels() map[string]string {
+	labels := make(map[string]string, len(n.labels))
+	for key, value := range n.labels {
+		labels[key] = value
+	}
+	return labels
+}
+
+// Unwrap returns the wrapped launcher.
+func (n named) Unwrap() Launcher {
+	return n.launcher
+}
+
+// WithName wraps the launcher to describe it by the name and the labels.
+func WithName(launcher Launcher, name string, labels map[string]string) Named {
+	return named{
+		launcher: launcher,
+		name:     name,
+		labels:   labels,
+	}
+}
+
+// NameOf returns the name of the launcher, or empty string, if it isn't Named.
+func NameOf(launcher Launcher) string {
+	if named, ok := as[Named](launcher); ok {
+		return named.Name()
+	}
+	return ""
+}
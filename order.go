@@ -0,0 +1,99 @@
+package servers
+
+import (
+	"context"
+)
+
+// Ordered delivers an interface to a launcher, which declares its order in Group.
+type Ordered interface {
+	Launcher
+	Readier
+	// Order returns the order of the launcher: lower orders are started earlier and stopped later.
+	Order() int
+}
+
+// ordered predetermines the consistency of the implementation Ordered.
+type ordered struct {
+	launcher Launcher
+	order    int
+}
+
+// Serve serving the launcher.
+func (o ordered) Serve() error {
+	return o.launcher.Serve()
+}
+
+// Stop stops the launcher.
+func (o ordered) Stop(ctx context.Context) error {
+	return o.launcher.Stop(ctx)
+}
+
+// Ready returns the readiness of the launcher, if it reports one, otherwise the closed channel.
+func (o ordered) Ready() <-chan struct{} {
+	return ready(o.launcher)
+}
+
+// Unwrap returns the wrapped launcher.
+func (o ordered) Unwrap() Launcher {
+	return o.launcher
+}
+
+// Order returns the order of the launcher.
+func (o ordered) Order() int {
+	return o.order
+}
+
+// WithOrder wraps the launcher to declare its order in Group.
+func WithOrder(launcher Launcher, order int) Ordered {
+	return ordered{
+		launcher: launcher,
+		order:    order,
+	}
+}
+
+// closed is the channel returned as the readiness of launchers, which don't report one.
+var closed = func() chan struct{} {
+	channel := make(chan struct{})
+	close(channel)
+	return channel
+}()
+
+// ready returns the readiness of the launcher, if it reports one, otherwise the closed channel.
+func ready(launcher Launcher) <-chan struct{} {
+	if readier, ok := as[Readier](launcher); ok {
+		return readier.Ready()
+	}
+	return closed
+}
+
+// orderOf returns the order of the launcher, zero if it doesn't declare one.
+func orderOf(launcher Launcher) int {
+	if ordered, ok := as[Ordered](launcher); ok {
+		return ordered.Order()
+	}
+	return 0
+}
+
+// wrapper delivers an interface to a launcher, which decorates another one.
+type wrapper interface {
+	// Unwrap returns the decorated launcher.
+	Unwrap() Launcher
+}
+
+// as finds the first launcher implementing T in the chain of wrappers.
+func as[T any](launcher Launcher) (T, bool) {
+	for launcher != nil {
+		if target, ok := launcher.(T); ok {
+			return target, true
+		}
+
+		wrapper, ok := launcher.(wrapper)
+		if !ok {
+			break
+		}
+		launcher = wrapper.Unwrap()
+	}
+
+	var zero T
+	return zero, false
+}
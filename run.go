@@ -0,0 +1,33 @@
+package servers
+
+import (
+	"context"
+	"errors"
+)
+
+// Run serves the launcher until ctx is done, after that stops it and waits for Serve to return.
+// The error of Serve is returned, if it exits before ctx is done, otherwise the errors of Stop and Serve are joined,
+// ErrAlreadyStopped and ErrServerClosed are filtered out as the clean exits.
+func Run(ctx context.Context, launcher Launcher) error {
+	serving := make(chan error, 1)
+	go func() {
+		serving <- launcher.Serve()
+	}()
+
+	select {
+	case err := <-serving:
+		return err
+	case <-ctx.Done():
+	}
+
+	stopErr := launcher.Stop(context.WithoutCancel(ctx))
+	if errors.Is(stopErr, ErrAlreadyStopped) {
+		stopErr = nil
+	}
+
+	serveErr := <-serving
+	if errors.Is(serveErr, ErrServerClosed) {
+		serveErr = nil
+	}
+	return errors.Join(stopErr, serveErr)
+}
@@ -0,0 +1,95 @@
+package servers_test
+
+import (
+	"context"
+	"errors"
+	"github.com/golang-mixins/servers"
+	"github.com/golang-mixins/servers/mock"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	errFailed := errors.New("failed")
+	errStop := errors.New("stop failed")
+
+	tests := []struct {
+		name    string
+		config  mock.Config
+		cancel  bool
+		wantErr error
+	}{
+		{
+			name:   "stopped by ctx",
+			config: mock.Config{},
+			cancel: true,
+		},
+		{
+			name:    "serve fails",
+			config:  mock.Config{ServeErrors: []error{errFailed}},
+			wantErr: errFailed,
+		},
+		{
+			name:    "stop fails",
+			config:  mock.Config{StopError: errStop},
+			cancel:  true,
+			wantErr: errStop,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			launcher := newMock(t, test.config)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if test.cancel {
+				go func() {
+					<-launcher.Ready()
+					cancel()
+				}()
+			}
+
+			err := servers.Run(ctx, launcher)
+			if test.wantErr == nil && err != nil {
+				t.Fatalf("Run: %v", err)
+			}
+			if test.wantErr != nil && !errors.Is(err, test.wantErr) {
+				t.Fatalf("Run: got %v, want %v", err, test.wantErr)
+			}
+			if calls := launcher.CallsOf(mock.MethodServe); calls != 1 {
+				t.Fatalf("Serve hasn't returned before Run")
+			}
+		})
+	}
+}
+
+func TestRunWaitsForServeAfterFailedStop(t *testing.T) {
+	launcher := newMock(t, mock.Config{StopDelay: time.Second})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-launcher.Ready()
+		cancel()
+	}()
+
+	stopCtx, stopCancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer stopCancel()
+	err := servers.Run(ctx, stoppingWithin{Launcher: launcher, ctx: stopCtx})
+	if !errors.Is(err, servers.ErrShutdownTimeout) {
+		t.Fatalf("Run: got %v, want ErrShutdownTimeout", err)
+	}
+	if calls := launcher.CallsOf(mock.MethodServe); calls != 1 {
+		t.Fatal("Serve hasn't returned before Run")
+	}
+}
+
+// stoppingWithin stops the launcher within ctx instead of the one passed to Stop.
+type stoppingWithin struct {
+	*mock.Launcher
+	ctx context.Context
+}
+
+func (s stoppingWithin) Stop(context.Context) error {
+	return s.Launcher.Stop(s.ctx)
+}
@@ -12,3 +12,9 @@ type Launcher interface {
 	// Stop stops the server.
 	Stop(ctx context.Context) error
 }
+
+// Readier delivers an interface to a launcher, which reports its readiness to serve.
+type Readier interface {
+	// Ready returns the channel, which is closed once the launcher is ready to serve.
+	Ready() <-chan struct{}
+}
@@ -0,0 +1,78 @@
+// Package serversfx provides the integration of servers implementations with the lifecycle of uber-go/fx:
+// the launcher is served by OnStart and stopped by OnStop within the start and the stop timeouts of fx.App.
+package serversfx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golang-mixins/servers"
+	"go.uber.org/fx"
+)
+
+// Module registers the hook of servers.Launcher provided to fx.App (e.g. servers.Group of the launchers).
+var Module = fx.Module("servers", fx.Invoke(Register))
+
+// Register appends the hook of the launcher to the lifecycle, see Hook.
+func Register(lifecycle fx.Lifecycle, shutdowner fx.Shutdowner, launcher servers.Launcher) {
+	lifecycle.Append(Hook(launcher, shutdowner))
+}
+
+// Hook returns the hook serving the launcher by OnStart and stopping it by OnStop.
+// OnStart waits for the launcher to be ready, if it is servers.Readier, so the failure of Serve or
+// the start timeout fails the start of fx.App (the launcher is stopped then). Once started, the failure of Serve
+// shuts fx.App down with exit code 1 by shutdowner, unless it is nil. OnStop stops the launcher within ctx
+// of the stop timeout and waits for Serve to return.
+func Hook(launcher servers.Launcher, shutdowner fx.Shutdowner) fx.Hook {
+	serving := make(chan error, 1)
+	stopping := make(chan struct{})
+
+	return fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			exited := make(chan error, 1)
+			go func() {
+				err := launcher.Serve()
+				exited <- err
+				serving <- err
+			}()
+
+			if readier, ok := launcher.(servers.Readier); ok {
+				select {
+				case <-readier.Ready():
+				case err := <-exited:
+					return fmt.Errorf("can't serve: %w", err)
+				case <-ctx.Done():
+					launcher.Stop(context.WithoutCancel(ctx))
+					return fmt.Errorf("launcher isn't ready: %w", ctx.Err())
+				}
+			}
+
+			go func() {
+				select {
+				case err := <-exited:
+					if err != nil && shutdowner != nil {
+						shutdowner.Shutdown(fx.ExitCode(1))
+					}
+				case <-stopping:
+				}
+			}()
+
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			close(stopping)
+
+			err := launcher.Stop(ctx)
+			if errors.Is(err, servers.ErrAlreadyStopped) {
+				err = nil
+			}
+
+			select {
+			case serveErr := <-serving:
+				return errors.Join(err, serveErr)
+			case <-ctx.Done():
+				return errors.Join(err, fmt.Errorf("launcher isn't stopped: %w", ctx.Err()))
+			}
+		},
+	}
+}
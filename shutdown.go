@@ -0,0 +1,20 @@
+package servers
+
+import (
+	"context"
+	"time"
+)
+
+// ForceCloseTimeout is the least budget of the forced close, which starts once the graceful shutdown is exceeded.
+const ForceCloseTimeout = time.Second
+
+// ForceCloseBudget returns the time left for the forced close: the rest of the deadline of ctx,
+// but at least ForceCloseTimeout, so that the deadline exceeded by the graceful shutdown doesn't cut the forced close.
+func ForceCloseBudget(ctx context.Context) time.Duration {
+	if deadline, ok := ctx.Deadline(); ok {
+		if left := time.Until(deadline); left > ForceCloseTimeout {
+			return left
+		}
+	}
+	return ForceCloseTimeout
+}
@@ -0,0 +1,39 @@
+package servers
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// RunWithSignals runs the launcher like Run, until ctx is done or SIGINT or SIGTERM is received.
+// The second signal received while the launcher is stopping exits the process immediately with code 1.
+func RunWithSignals(ctx context.Context, launcher Launcher) error {
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		select {
+		case <-signals:
+			cancel()
+		case <-done:
+			return
+		}
+
+		select {
+		case <-signals:
+			os.Exit(1)
+		case <-done:
+		}
+	}()
+
+	return Run(ctx, launcher)
+}
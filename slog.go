@@ -0,0 +1,60 @@
+package servers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"runtime"
+	"time"
+)
+
+// slogLogger predetermines the consistency of the implementation Logger on top of *slog.Logger.
+// The unstructured messages of the underlying servers are logged at the warning level.
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+// Printf logs the unstructured message.
+func (l slogLogger) Printf(format string, v ...interface{}) {
+	l.log(slog.LevelWarn, fmt.Sprintf(format, v...))
+}
+
+// Info logs the lifecycle event.
+func (l slogLogger) Info(msg string, keysAndValues ...interface{}) {
+	l.log(slog.LevelInfo, msg, keysAndValues...)
+}
+
+// Error logs the failure.
+func (l slogLogger) Error(msg string, keysAndValues ...interface{}) {
+	l.log(slog.LevelError, msg, keysAndValues...)
+}
+
+// log emits the record, whose source is the caller of Logger rather than the adapter.
+func (l slogLogger) log(level slog.Level, msg string, keysAndValues ...interface{}) {
+	ctx := context.Background()
+	if !l.logger.Enabled(ctx, level) {
+		return
+	}
+
+	var pcs [1]uintptr
+	runtime.Callers(3, pcs[:])
+
+	record := slog.NewRecord(time.Now(), level, msg, pcs[0])
+	record.Add(keysAndValues...)
+	_ = l.logger.Handler().Handle(ctx, record)
+}
+
+// NewSlogLogger - constructor Logger on top of *slog.Logger, nil stands for slog.Default().
+func NewSlogLogger(logger *slog.Logger) Logger {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return slogLogger{logger: logger}
+}
+
+// NewJSONLogger - constructor Logger writing each message to w as a JSON object on a single line
+// with the stable keys "time", "level", "msg" followed by the attributes of the message.
+func NewJSONLogger(w io.Writer) Logger {
+	return NewSlogLogger(slog.New(slog.NewJSONHandler(w, nil)))
+}
@@ -0,0 +1,40 @@
+package servers
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+)
+
+// RemoveStaleSocket removes the unix domain socket left by the previous process, so that it can be bound again.
+// The socket is removed only if nothing accepts on it (the dial is refused), the socket still served by
+// the running process and any other file are kept with the error.
+func RemoveStaleSocket(path string) error {
+	info, err := os.Lstat(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("can't stat socket: %w", err)
+	}
+
+	if info.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("%s exists and isn't a socket", path)
+	}
+
+	conn, err := net.Dial("unix", path)
+	if err == nil {
+		conn.Close()
+		return fmt.Errorf("socket %s is in use", path)
+	}
+	if !errors.Is(err, syscall.ECONNREFUSED) {
+		return fmt.Errorf("can't check socket %s: %w", path, err)
+	}
+
+	if err = os.Remove(path); err != nil {
+		return fmt.Errorf("can't remove stale socket: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package servers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// SupervisorConfig delivers a set of settings for Supervisor implementation.
+// New creates the launcher for every (re)start. Backoff between restarts starts with MinBackoff and doubles up to
+// MaxBackoff, both backoff and restarts count are reset once the launcher serves longer than MaxBackoff.
+// MaxRestarts limits the consecutive restarts, zero means unlimited.
+// OnRestart, if set, is called before every restart with the error caused it.
+type SupervisorConfig struct {
+	New         func() (Launcher, error)
+	MinBackoff  time.Duration
+	MaxBackoff  time.Duration
+	MaxRestarts int
+	OnRestart   func(restart int, backoff time.Duration, err error)
+}
+
+// Validate validates SupervisorConfig according to predefined rules.
+func (c SupervisorConfig) Validate() error {
+	var errs []error
+
+	if c.New == nil {
+		errs = append(errs, errors.New("New can't be nil"))
+	}
+
+	if c.MinBackoff <= 0 {
+		errs = append(errs, errors.New("MinBackoff must be positive"))
+	}
+
+	if c.MaxBackoff < c.MinBackoff {
+		errs = append(errs, errors.New("MaxBackoff can't be less than MinBackoff"))
+	}
+
+	if c.MaxRestarts < 0 {
+		errs = append(errs, errors.New("MaxRestarts can't be negative"))
+	}
+	return errors.Join(errs...)
+}
+
+// Supervisor predetermines the consistency of the implementation Launcher, which restarts the supervised launcher
+// with backoff, when its Serve returns an error.
+// Using the methods of the structure, without being initialized by the NewSupervisor() constructor, will lead to panic.
+type Supervisor struct {
+	config   SupervisorConfig
+	mutex    *sync.Mutex
+	current  Launcher
+	stopped  bool
+	stopping chan struct{}
+}
+
+// Serve serving the supervised launcher, until it exits without error, is stopped or exceeds MaxRestarts.
+func (s *Supervisor) Serve() error {
+	restarts := 0
+	backoff := s.config.MinBackoff
+
+	for {
+		started := time.Now()
+		err := s.serveOnce()
+		if err == nil {
+			return nil
+		}
+
+		if time.Since(started) > s.config.MaxBackoff {
+			restarts = 0
+			backoff = s.config.MinBackoff
+		}
+
+		if s.config.MaxRestarts != 0 && restarts >= s.config.MaxRestarts {
+			return fmt.Errorf("restarts limit %d exceeded: %w", s.config.MaxRestarts, err)
+		}
+		restarts++
+
+		if s.config.OnRestart != nil {
+			s.config.OnRestart(restarts, backoff, err)
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-s.stopping:
+			timer.Stop()
+			return nil
+		}
+
+		backoff *= 2
+		if backoff > s.config.MaxBackoff {
+			backoff = s.config.MaxBackoff
+		}
+	}
+}
+
+// serveOnce creates the launcher and serves it. Nil is returned, if the supervisor is stopped.
+// The launcher failed to serve is stopped, so that its resources are released before it is replaced.
+func (s *Supervisor) serveOnce() error {
+	launcher, err := s.config.New()
+	if err != nil {
+		return fmt.Errorf("can't create launcher: %w", err)
+	}
+
+	s.mutex.Lock()
+	if s.stopped {
+		s.mutex.Unlock()
+		// the supervisor is stopped while the launcher was created, it's a clean exit.
+		stopLauncher(launcher)
+		return nil
+	}
+	s.current = launcher
+	s.mutex.Unlock()
+
+	err = launcher.Serve()
+
+	s.mutex.Lock()
+	s.current = nil
+	stopped := s.stopped
+	s.mutex.Unlock()
+
+	if stopped {
+		return nil
+	}
+	if err != nil {
+		return errors.Join(err, stopLauncher(launcher))
+	}
+	return nil
+}
+
+// stopLauncher stops the launcher, which isn't served by the supervisor anymore.
+func stopLauncher(launcher Launcher) error {
+	err := launcher.Stop(context.Background())
+	if err != nil && !errors.Is(err, ErrAlreadyStopped) {
+		return fmt.Errorf("can't stop launcher: %w", err)
+	}
+	return nil
+}
+
+// Stop stops the supervised launcher and prevents the restarts.
+func (s *Supervisor) Stop(ctx context.Context) error {
+	s.mutex.Lock()
+	if s.stopped {
+		s.mutex.Unlock()
+		return ErrAlreadyStopped
+	}
+	s.stopped = true
+	close(s.stopping)
+	current := s.current
+	s.mutex.Unlock()
+
+	if current == nil {
+		return nil
+	}
+	return current.Stop(ctx)
+}
+
+// NewSupervisor - constructor Supervisor.
+func NewSupervisor(cfg SupervisorConfig) (*Supervisor, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &Supervisor{
+		config:   cfg,
+		mutex:    new(sync.Mutex),
+		stopping: make(chan struct{}),
+	}, nil
+}
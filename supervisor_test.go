@@ -0,0 +1,141 @@
+package servers_test
+
+import (
+	"context"
+	"errors"
+	"github.com/golang-mixins/servers"
+	"github.com/golang-mixins/servers/mock"
+	"sync"
+	"testing"
+	"time"
+)
+
+// scripted returns New of SupervisorConfig creating the launchers, each of which fails once with the error
+// of the script in turn, the launchers past the script serve until Stop.
+func scripted(t *testing.T, errs ...error) (func() (servers.Launcher, error), func() []*mock.Launcher) {
+	mutex := new(sync.Mutex)
+	var created []*mock.Launcher
+
+	create := func() (servers.Launcher, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		cfg := mock.Config{}
+		if len(created) < len(errs) {
+			cfg.ServeErrors = []error{errs[len(created)]}
+		}
+		launcher := newMock(t, cfg)
+		created = append(created, launcher)
+		return launcher, nil
+	}
+
+	launchers := func() []*mock.Launcher {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		return append([]*mock.Launcher(nil), created...)
+	}
+	return create, launchers
+}
+
+func TestSupervisorRestarts(t *testing.T) {
+	errFailed := errors.New("failed")
+	create, launchers := scripted(t, errFailed, errFailed)
+
+	var backoffs []time.Duration
+	supervisor, err := servers.NewSupervisor(servers.SupervisorConfig{
+		New:        create,
+		MinBackoff: time.Millisecond,
+		MaxBackoff: time.Second,
+		OnRestart: func(restart int, backoff time.Duration, err error) {
+			backoffs = append(backoffs, backoff)
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewSupervisor: %v", err)
+	}
+
+	serving := make(chan error, 1)
+	go func() {
+		serving <- supervisor.Serve()
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for len(launchers()) < 3 {
+		if time.Now().After(deadline) {
+			t.Fatal("launcher isn't restarted")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	<-launchers()[2].Ready()
+
+	if err = supervisor.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	if err = <-serving; err != nil {
+		t.Fatalf("Serve: %v", err)
+	}
+
+	if len(backoffs) != 2 || backoffs[0] != time.Millisecond || backoffs[1] != 2*time.Millisecond {
+		t.Fatalf("backoffs: got %v, want [1ms 2ms]", backoffs)
+	}
+	for i, launcher := range launchers() {
+		if !launcher.Stopped() {
+			t.Errorf("launcher %d isn't stopped", i)
+		}
+	}
+}
+
+func TestSupervisorRestartsLimit(t *testing.T) {
+	errFailed := errors.New("failed")
+	create, launchers := scripted(t, errFailed, errFailed, errFailed)
+
+	supervisor, err := servers.NewSupervisor(servers.SupervisorConfig{
+		New:         create,
+		MinBackoff:  time.Millisecond,
+		MaxBackoff:  time.Millisecond,
+		MaxRestarts: 2,
+	})
+	if err != nil {
+		t.Fatalf("NewSupervisor: %v", err)
+	}
+
+	if err = supervisor.Serve(); !errors.Is(err, errFailed) {
+		t.Fatalf("Serve: got %v, want %v", err, errFailed)
+	}
+	if created := len(launchers()); created != 3 {
+		t.Fatalf("launchers created: got %d, want 3", created)
+	}
+}
+
+func TestSupervisorStoppedDuringNew(t *testing.T) {
+	var supervisor *servers.Supervisor
+	var launcher *mock.Launcher
+	restarted := false
+
+	supervisor, err := servers.NewSupervisor(servers.SupervisorConfig{
+		New: func() (servers.Launcher, error) {
+			supervisor.Stop(context.Background())
+			launcher = newMock(t, mock.Config{})
+			return launcher, nil
+		},
+		MinBackoff: time.Millisecond,
+		MaxBackoff: time.Millisecond,
+		OnRestart: func(int, time.Duration, error) {
+			restarted = true
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewSupervisor: %v", err)
+	}
+
+	if err = supervisor.Serve(); err != nil {
+		t.Fatalf("Serve: %v", err)
+	}
+	if restarted {
+		t.Fatal("launcher is restarted after Stop")
+	}
+	if !launcher.Stopped() {
+		t.Fatal("launcher created during Stop isn't stopped")
+	}
+}
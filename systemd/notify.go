@@ -0,0 +1,64 @@
+package systemd
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"time"
+)
+
+// States of the service notified to systemd.
+const (
+	Ready    = "READY=1"
+	Stopping = "STOPPING=1"
+	Watchdog = "WATCHDOG=1"
+)
+
+// Notify sends the state to systemd (sd_notify).
+// False is returned without error if the notification socket isn't provided.
+func Notify(state string) (bool, error) {
+	socket := os.Getenv("NOTIFY_SOCKET")
+	if socket == "" {
+		return false, nil
+	}
+
+	if socket[0] == '@' {
+		socket = "\x00" + socket[1:]
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		return false, fmt.Errorf("can't dial notification socket: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err = conn.Write([]byte(state)); err != nil {
+		return false, fmt.Errorf("can't notify %s: %w", state, err)
+	}
+	return true, nil
+}
+
+// WatchdogInterval returns the watchdog timeout of the service, within which Watchdog must be notified.
+// Zero is returned without error if the watchdog isn't enabled for the process.
+func WatchdogInterval() (time.Duration, error) {
+	usec := os.Getenv("WATCHDOG_USEC")
+	if usec == "" {
+		return 0, nil
+	}
+
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0, nil
+	}
+
+	interval, err := strconv.ParseInt(usec, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("can't parse WATCHDOG_USEC: %w", err)
+	}
+
+	if interval <= 0 {
+		return 0, errors.New("WATCHDOG_USEC must be positive")
+	}
+	return time.Duration(interval) * time.Microsecond, nil
+}
@@ -0,0 +1,72 @@
+// Package systemd provides the integration of servers implementations with systemd.
+package systemd
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// listenFDsStart is the first file descriptor passed by systemd.
+const listenFDsStart = 3
+
+// Listeners returns the listeners passed by systemd socket activation, in the order of the socket unit.
+// Nil is returned if the process isn't socket activated.
+// The environment of the activation is unset, so the child processes don't inherit it.
+func Listeners() ([]net.Listener, error) {
+	files, err := activationFiles()
+	if err != nil || len(files) == 0 {
+		return nil, err
+	}
+
+	listeners := make([]net.Listener, 0, len(files))
+	for _, file := range files {
+		listener, err := net.FileListener(file)
+		file.Close()
+		if err != nil {
+			for _, listener := range listeners {
+				listener.Close()
+			}
+			return nil, fmt.Errorf("can't use file descriptor %s as listener: %w", file.Name(), err)
+		}
+		listeners = append(listeners, listener)
+	}
+	return listeners, nil
+}
+
+// activationFiles returns the files passed by systemd socket activation.
+func activationFiles() ([]*os.File, error) {
+	defer func() {
+		os.Unsetenv("LISTEN_PID")
+		os.Unsetenv("LISTEN_FDS")
+		os.Unsetenv("LISTEN_FDNAMES")
+	}()
+
+	pid, ok := os.LookupEnv("LISTEN_PID")
+	if !ok {
+		return nil, nil
+	}
+
+	if pid != strconv.Itoa(os.Getpid()) {
+		return nil, nil
+	}
+
+	count, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil {
+		return nil, fmt.Errorf("can't parse LISTEN_FDS: %w", err)
+	}
+
+	names := strings.Split(os.Getenv("LISTEN_FDNAMES"), ":")
+
+	files := make([]*os.File, 0, count)
+	for i := 0; i < count; i++ {
+		name := "LISTEN_FD_" + strconv.Itoa(listenFDsStart+i)
+		if i < len(names) && names[i] != "" {
+			name = names[i]
+		}
+		files = append(files, os.NewFile(uintptr(listenFDsStart+i), name))
+	}
+	return files, nil
+}
@@ -0,0 +1,28 @@
+package servers
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentation is the name of the tracers of the launchers.
+const instrumentation = "github.com/golang-mixins/servers"
+
+// Tracer returns the tracer of the launchers taken from the provider,
+// or from the global OpenTelemetry provider, if the provider is nil.
+func Tracer(provider trace.TracerProvider) trace.Tracer {
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+	return provider.Tracer(instrumentation)
+}
+
+// EndSpan records err, if it isn't nil, on the span and ends the span.
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
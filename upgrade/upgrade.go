@@ -0,0 +1,373 @@
+//go:build unix
+
+// Package upgrade provides zero-downtime upgrades of the process: the new process inherits the listeners
+// of the old one over a unix domain socket, after that the old one drains and exits.
+package upgrade
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/golang-mixins/servers"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// readyMessage is sent by the new process to the old one, once it serves the inherited listeners.
+const readyMessage = "ready"
+
+// maxListeners bounds the count of listeners passed in a single handoff.
+const maxListeners = 64
+
+// Config delivers a set of settings for Upgrader implementation.
+// SocketPath is the unix domain socket, where the old process hands off its listeners to the new one.
+// HandoffTimeout bounds the time the new process may take to become ready after connecting to the old one.
+type Config struct {
+	SocketPath     string
+	HandoffTimeout time.Duration
+	Logger         servers.Logger
+}
+
+// Validate validates Config according to predefined rules.
+func (c Config) Validate() error {
+	var errs []error
+
+	if c.SocketPath == "" {
+		errs = append(errs, errors.New("SocketPath can't be empty"))
+	}
+
+	if c.HandoffTimeout <= 0 {
+		errs = append(errs, errors.New("HandoffTimeout must be positive"))
+	}
+
+	if c.Logger == nil {
+		errs = append(errs, errors.New("Logger can't be nil"))
+	}
+	return errors.Join(errs...)
+}
+
+// Upgrader predetermines the consistency of the listeners handoff between the old and the new processes.
+// Using the methods of the structure, without being initialized by the New() constructor, will lead to panic.
+type Upgrader struct {
+	socketPath     string
+	handoffTimeout time.Duration
+	logger         servers.Logger
+	mutex          *sync.Mutex
+	inherited      map[string]*os.File
+	listeners      map[string]net.Listener
+	parent         *net.UnixConn
+	handoff        *net.UnixListener
+	exit           chan struct{}
+}
+
+// Listen returns the listener inherited from the old process, if any, otherwise binds a new one.
+// The listener is handed off to the next process on upgrade.
+func (u *Upgrader) Listen(network, address string) (net.Listener, error) {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+
+	key := network + " " + address
+	if _, ok := u.listeners[key]; ok {
+		return nil, fmt.Errorf("listener %s already exists", key)
+	}
+
+	var listener net.Listener
+	if file, ok := u.inherited[key]; ok {
+		delete(u.inherited, key)
+
+		var err error
+		listener, err = net.FileListener(file)
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("can't use inherited listener %s: %w", key, err)
+		}
+		u.logger.Info("listener inherited", "key", key)
+	} else {
+		var err error
+		listener, err = net.Listen(network, address)
+		if err != nil {
+			return nil, fmt.Errorf("can't listen %s: %w", key, err)
+		}
+	}
+
+	// The socket file is shared with the next process, so it mustn't be removed, when the old one stops serving.
+	if unixListener, ok := listener.(*net.UnixListener); ok {
+		unixListener.SetUnlinkOnClose(false)
+	}
+
+	u.listeners[key] = listener
+	return listener, nil
+}
+
+// Ready reports to the old process, that the new one serves the inherited listeners, so the old one can drain,
+// and starts accepting the next upgrade. Inherited listeners, which weren't requested by Listen, are closed.
+func (u *Upgrader) Ready() error {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+
+	if u.handoff != nil {
+		return errors.New("upgrader is already ready")
+	}
+
+	for key, file := range u.inherited {
+		u.logger.Info("unused inherited listener closed", "key", key)
+		file.Close()
+		delete(u.inherited, key)
+	}
+
+	if u.parent != nil {
+		if err := u.notifyParent(); err != nil {
+			return err
+		}
+	}
+
+	if err := servers.RemoveStaleSocket(u.socketPath); err != nil {
+		return err
+	}
+
+	handoff, err := net.ListenUnix("unix", &net.UnixAddr{Name: u.socketPath, Net: "unix"})
+	if err != nil {
+		return fmt.Errorf("can't listen handoff socket: %w", err)
+	}
+	u.handoff = handoff
+
+	go u.accept(handoff)
+
+	return nil
+}
+
+// Exit returns the channel, which is closed once the new process has taken over the listeners.
+// The launchers serving the listeners should be stopped after that.
+func (u *Upgrader) Exit() <-chan struct{} {
+	return u.exit
+}
+
+// Close stops accepting the upgrades.
+func (u *Upgrader) Close() error {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+
+	if u.parent != nil {
+		u.parent.Close()
+		u.parent = nil
+	}
+
+	if u.handoff != nil {
+		return u.handoff.Close()
+	}
+	return nil
+}
+
+// notifyParent sends readyMessage to the old process and waits until it stops accepting the upgrades.
+func (u *Upgrader) notifyParent() error {
+	defer func() {
+		u.parent.Close()
+		u.parent = nil
+	}()
+
+	if err := u.parent.SetDeadline(time.Now().Add(u.handoffTimeout)); err != nil {
+		return fmt.Errorf("can't set handoff deadline: %w", err)
+	}
+
+	if _, err := u.parent.Write([]byte(readyMessage)); err != nil {
+		return fmt.Errorf("can't notify old process: %w", err)
+	}
+
+	// The old process closes the connection after closing the handoff socket.
+	if _, err := io.Copy(io.Discard, u.parent); err != nil {
+		return fmt.Errorf("can't wait for old process: %w", err)
+	}
+
+	u.logger.Info("old process notified")
+	return nil
+}
+
+// accept hands off the listeners to the connecting processes, until one of them is ready.
+func (u *Upgrader) accept(handoff *net.UnixListener) {
+	for {
+		conn, err := handoff.AcceptUnix()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				u.logger.Error("handoff accept error", "error", err)
+			}
+			return
+		}
+
+		if err = u.handOver(conn); err != nil {
+			u.logger.Error("handoff error", "error", err)
+			continue
+		}
+
+		u.logger.Info("listeners handed off, exiting")
+		close(u.exit)
+		return
+	}
+}
+
+// handOver passes the listeners to the new process and waits until it is ready.
+func (u *Upgrader) handOver(conn *net.UnixConn) error {
+	defer conn.Close()
+
+	keys, files, err := u.files()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		for _, file := range files {
+			file.Close()
+		}
+	}()
+
+	data, err := json.Marshal(keys)
+	if err != nil {
+		return fmt.Errorf("can't marshal listeners: %w", err)
+	}
+
+	fds := make([]int, 0, len(files))
+	for _, file := range files {
+		fds = append(fds, int(file.Fd()))
+	}
+
+	if err = conn.SetDeadline(time.Now().Add(u.handoffTimeout)); err != nil {
+		return fmt.Errorf("can't set handoff deadline: %w", err)
+	}
+
+	if _, _, err = conn.WriteMsgUnix(data, syscall.UnixRights(fds...), nil); err != nil {
+		return fmt.Errorf("can't pass listeners: %w", err)
+	}
+
+	message := make([]byte, len(readyMessage))
+	if _, err = io.ReadFull(conn, message); err != nil {
+		return fmt.Errorf("new process didn't become ready: %w", err)
+	}
+
+	if string(message) != readyMessage {
+		return fmt.Errorf("unexpected message from new process: %q", message)
+	}
+
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+
+	if err = u.handoff.Close(); err != nil {
+		return fmt.Errorf("can't close handoff socket: %w", err)
+	}
+	return nil
+}
+
+// files duplicates the file descriptors of the listeners.
+func (u *Upgrader) files() ([]string, []*os.File, error) {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+
+	if len(u.listeners) > maxListeners {
+		return nil, nil, fmt.Errorf("can't pass more than %d listeners", maxListeners)
+	}
+
+	keys := make([]string, 0, len(u.listeners))
+	files := make([]*os.File, 0, len(u.listeners))
+	for key, listener := range u.listeners {
+		filer, ok := listener.(interface{ File() (*os.File, error) })
+		if !ok {
+			u.logger.Error("listener can't be passed", "key", key)
+			continue
+		}
+
+		file, err := filer.File()
+		if err != nil {
+			for _, file := range files {
+				file.Close()
+			}
+			return nil, nil, fmt.Errorf("can't get file of listener %s: %w", key, err)
+		}
+
+		keys = append(keys, key)
+		files = append(files, file)
+	}
+	return keys, files, nil
+}
+
+// inherit receives the listeners from the old process.
+func (u *Upgrader) inherit(conn *net.UnixConn) error {
+	if err := conn.SetReadDeadline(time.Now().Add(u.handoffTimeout)); err != nil {
+		return fmt.Errorf("can't set handoff deadline: %w", err)
+	}
+
+	data := make([]byte, 64*1024)
+	oob := make([]byte, syscall.CmsgSpace(maxListeners*4))
+	n, oobn, _, _, err := conn.ReadMsgUnix(data, oob)
+	if err != nil {
+		return fmt.Errorf("can't receive listeners: %w", err)
+	}
+
+	var keys []string
+	if err = json.Unmarshal(data[:n], &keys); err != nil {
+		return fmt.Errorf("can't unmarshal listeners: %w", err)
+	}
+
+	messages, err := syscall.ParseSocketControlMessage(oob[:oobn])
+	if err != nil {
+		return fmt.Errorf("can't parse control message: %w", err)
+	}
+
+	var fds []int
+	for i := range messages {
+		rights, err := syscall.ParseUnixRights(&messages[i])
+		if err != nil {
+			return fmt.Errorf("can't parse unix rights: %w", err)
+		}
+		fds = append(fds, rights...)
+	}
+
+	if len(fds) != len(keys) {
+		for _, fd := range fds {
+			syscall.Close(fd)
+		}
+		return fmt.Errorf("received %d file descriptors for %d listeners", len(fds), len(keys))
+	}
+
+	for i, key := range keys {
+		u.inherited[key] = os.NewFile(uintptr(fds[i]), key)
+	}
+
+	if err = conn.SetReadDeadline(time.Time{}); err != nil {
+		return fmt.Errorf("can't reset handoff deadline: %w", err)
+	}
+	return nil
+}
+
+// New - constructor Upgrader.
+// If the old process accepts the upgrades on SocketPath, its listeners are inherited.
+func New(cfg Config) (*Upgrader, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	upgrader := &Upgrader{
+		socketPath:     cfg.SocketPath,
+		handoffTimeout: cfg.HandoffTimeout,
+		mutex:          new(sync.Mutex),
+		inherited:      make(map[string]*os.File),
+		listeners:      make(map[string]net.Listener),
+		exit:           make(chan struct{}),
+	}
+
+	upgrader.logger = cfg.Logger
+
+	conn, err := net.DialUnix("unix", nil, &net.UnixAddr{Name: cfg.SocketPath, Net: "unix"})
+	if err != nil {
+		upgrader.logger.Info("no old process found, starting fresh")
+		return upgrader, nil
+	}
+
+	if err = upgrader.inherit(conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	upgrader.parent = conn
+
+	return upgrader, nil
+}